@@ -0,0 +1,117 @@
+package gofe
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// bind binds the params of the step's named capture groups to the fields of a
+// struct, if the first argument of the func t, other than any *Step, is a
+// struct. The struct replaces the params, followed by the params of any unnamed
+// groups in order. A field is bound by its gofe tag or otherwise its name,
+// ignoring case.
+//
+//		type Order struct {
+//			Qty  int `gofe:"qty"`
+//			Item string
+//		}
+//
+//		s.Add(`^I order (?P<qty>\d+) (?P<item>\w+)$`, func(t Testing) func(Order) {
+//			...
+//		})
+//
+func (s *step) bind(t reflect.Type, a []interface{}) ([]interface{}, error) {
+	if s.reg == nil {
+		return a, nil
+	}
+
+	names := s.reg.SubexpNames()[1:]
+	if len(names) > len(a) {
+		names = names[:len(a)]
+	}
+	if !named(names) {
+		return a, nil
+	}
+
+	i := 0
+	if t.NumIn() > 0 && t.In(0) == reflect.TypeOf(st) {
+		i++
+	}
+	if i >= t.NumIn() {
+		return a, nil
+	}
+
+	typ := t.In(i)
+	if typ.Kind() != reflect.Struct ||
+		reflect.PtrTo(typ).Implements(textUnmarshalerType) ||
+		s.converters[typ] != nil {
+		return a, nil
+	}
+
+	v := reflect.New(typ).Elem()
+	var unnamed []interface{}
+	for j, name := range names {
+		if name == "" {
+			unnamed = append(unnamed, a[j])
+
+			continue
+		}
+
+		p, ok := a[j].(*param)
+		if !ok {
+			continue
+		}
+
+		f, ok := field(typ, name)
+		if !ok {
+			return nil, fmt.Errorf("(?P<%s>): no field of %s", name, typ)
+		}
+
+		fv, err := checkParam(p, f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s as %s: %s", f.Name, p, f.Type, err)
+		}
+
+		v.FieldByIndex(f.Index).Set(fv)
+	}
+
+	b := append([]interface{}{v.Interface()}, unnamed...)
+
+	return append(b, a[len(names):]...), nil
+}
+
+// named checks if any of the capture groups are named
+func named(names []string) bool {
+	for _, v := range names {
+		if v != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// field returns the exported field tagged `gofe:"name"`, or otherwise named
+// name, ignoring case
+func field(t reflect.Type, name string) (reflect.StructField, bool) {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" {
+			fields = append(fields, f)
+		}
+	}
+
+	for _, f := range fields {
+		if f.Tag.Get("gofe") == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if f.Tag.Get("gofe") == "" && strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
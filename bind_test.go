@@ -0,0 +1,123 @@
+package gofe
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+type Order struct {
+	Qty    int `gofe:"qty"`
+	Item   string
+	Coupon *string
+	Price  Money
+	At     time.Time
+
+	note string
+}
+
+func TestNamedCaptureGroupsAreBoundToAStruct(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Convert(parseMoney)
+	s.Add(`^(?P<qty>\d+) (?P<item>\w+) at (?P<price>\S+)(?: with (?P<coupon>\w+))?$`,
+		func(t Testing) func(*Step, Order, Table) {
+			return func(s *Step, o Order, tbl Table) {
+				got = append(got, o, tbl, s.Absent(3))
+			}
+		})
+
+	fe := New(tT, s)
+	fe.StrictArgs = true
+	fe.Step("3 apples at $1.50", Table{{"a"}})
+	fe.Step("1 pear at $0.99 with HALF", Table{{"b"}})
+
+	half := "HALF"
+
+	assert.Equal(t, []interface{}{
+		Order{Qty: 3, Item: "apples", Price: Money{150}}, Table{{"a"}}, true,
+		Order{Qty: 1, Item: "pear", Price: Money{99}, Coupon: &half}, Table{{"b"}}, false,
+	}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestUnnamedCaptureGroupsFollowTheBoundStruct(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Add(`^(\w+) orders (?P<qty>\d+) (?P<item>\w+) by (\w+)$`,
+		func(t Testing) func(Order, string, Colour, Table) {
+			return func(o Order, name string, c Colour, tbl Table) {
+				got = append(got, o, name, c, tbl)
+			}
+		})
+
+	fe := New(tT, s)
+	fe.StrictArgs = true
+	fe.Step("Batman orders 3 apples by post", Table{{"a"}})
+
+	assert.Equal(t, []interface{}{
+		Order{Qty: 3, Item: "apples"}, "Batman", Colour("post"), Table{{"a"}},
+	}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestStructsAreOnlyBoundFromNamedCaptureGroups(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Add(`^at (\S+)$`, func(t Testing) func(time.Time) {
+		return func(at time.Time) {
+			got = append(got, at)
+		}
+	})
+	s.Add(`^at (?P<at>\S+) exactly$`, func(t Testing) func(time.Time) {
+		return func(at time.Time) {
+			got = append(got, at)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("at 2014-01-02T15:04:05Z")
+	fe.Step("at 2014-01-02T15:04:05Z exactly")
+
+	at := time.Date(2014, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, []interface{}{at, at}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestNamedCaptureGroupErrors(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add(`^(?P<qty>\w+) (?P<item>\w+)$`, func(t Testing) func(Order) {
+		return func(Order) {}
+	})
+	s.Add(`^(?P<quantity>\d+) of them$`, func(t Testing) func(Order) {
+		return func(Order) {}
+	})
+	s.Add(`^(?P<note>\w+) is noted$`, func(t Testing) func(Order) {
+		return func(Order) {}
+	})
+
+	fe := New(tT, s)
+	fe.Step("x apples")
+	fe.Step("3 of them")
+	fe.Step("this is noted")
+
+	assert.Equal(t, []string{
+		"`x apples`: field Qty: \"x\" as int: " +
+			"strconv.ParseInt: parsing \"x\": invalid syntax",
+		"`3 of them`: (?P<quantity>): no field of gofe.Order",
+		"`this is noted`: (?P<note>): no field of gofe.Order",
+	}, tT.fatalfs)
+}
@@ -0,0 +1,202 @@
+package gofe
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	stringType          = reflect.TypeOf("")
+)
+
+// converters convert captured text to their type
+type converters map[reflect.Type]func(string) (interface{}, error)
+
+// Convert registers fn, which must implement func(string) (T, error), to
+// convert any captured text given to a T argument of the Steps' StepFuncs. A
+// converter takes the place of any built in conversion of T. Like Add it always
+// returns nil.
+//
+//		s.Convert(func(s string) (Money, error) {
+//			return ParseMoney(s)
+//		})
+//		s.Add(`^I pay (\S+)$`, func(t Testing) func(Money) {
+//			...
+//		})
+//
+func (s Steps) Convert(fn interface{}) interface{} {
+	s.mustBeNew()
+
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || !isTransform(v.Type()) {
+		panic("converters must implement func(string) (T, error)")
+	}
+
+	t := v.Type().Out(0)
+	if _, ok := s.converters[t]; ok {
+		panic(fmt.Sprintf("converter for %s already exists", t))
+	}
+
+	s.converters[t] = transform(v)
+
+	return nil
+}
+
+// convert converts the string to the type t. Types implementing
+// encoding.TextUnmarshaler are unmarshaled, which includes RFC3339 time.Time,
+// time.Duration is parsed by time.ParseDuration and otherwise any basic kind,
+// []byte or pointer to one of those is converted.
+func convert(s string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		v := reflect.New(t)
+		err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		if err != nil {
+			return reflect.Zero(t), err
+		}
+
+		return v.Elem(), nil
+	}
+
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+
+		return reflect.ValueOf(d), err
+	}
+
+	var err error
+	var p interface{}
+	switch t.Kind() {
+	case reflect.String:
+		p = s
+
+	case reflect.Bool:
+		p, err = strconv.ParseBool(s)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p, err = strconv.ParseInt(s, 0, t.Bits())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		p, err = strconv.ParseUint(s, 0, t.Bits())
+
+	case reflect.Float32, reflect.Float64:
+		p, err = strconv.ParseFloat(s, t.Bits())
+
+	case reflect.Complex64, reflect.Complex128:
+		var c complex128
+		_, err = fmt.Sscan(s, &c)
+		p = c
+
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return reflect.Zero(t), fmt.Errorf("cannot convert %q to %s", s, t)
+		}
+
+		p = []byte(s)
+
+	case reflect.Ptr:
+		v, err := convert(s, t.Elem())
+		if err != nil {
+			return reflect.Zero(t), err
+		}
+
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(v)
+
+		return ptr, nil
+
+	case reflect.Interface:
+		if !stringType.Implements(t) {
+			return reflect.Zero(t), fmt.Errorf("cannot convert %q to %s", s, t)
+		}
+
+		return reflect.ValueOf(s), nil
+
+	default:
+		return reflect.Zero(t), fmt.Errorf("cannot convert %q to %s", s, t)
+	}
+	if err != nil {
+		return reflect.Zero(t), err
+	}
+
+	return reflect.ValueOf(p).Convert(t), nil
+}
+
+var (
+	errRange   = errors.New("value out of range")
+	errInteger = errors.New("not an integer")
+)
+
+// coerce converts the numeric value v to the numeric type t, failing if the
+// value is out of range of t or a float given to an integer type. ok is false
+// if either is not numeric.
+func coerce(v reflect.Value, t reflect.Type) (c reflect.Value, ok bool, err error) {
+	from, to := numeric(v.Kind()), numeric(t.Kind())
+	if from == 0 || to == 0 {
+		return v, false, nil
+	}
+
+	z := reflect.New(t).Elem()
+	switch from {
+	case reflect.Int:
+		i := v.Int()
+		if to == reflect.Int && z.OverflowInt(i) ||
+			to == reflect.Uint && (i < 0 || z.OverflowUint(uint64(i))) {
+			err = errRange
+		}
+
+	case reflect.Uint:
+		u := v.Uint()
+		if to == reflect.Int && (u > math.MaxInt64 || z.OverflowInt(int64(u))) ||
+			to == reflect.Uint && z.OverflowUint(u) {
+			err = errRange
+		}
+
+	case reflect.Float64:
+		f := v.Float()
+		switch {
+		case to == reflect.Float64:
+			if z.OverflowFloat(f) {
+				err = errRange
+			}
+		case f != math.Trunc(f):
+			err = errInteger
+		case to == reflect.Int && (f < math.MinInt64 || f >= math.MaxInt64 ||
+			z.OverflowInt(int64(f))):
+			err = errRange
+		case to == reflect.Uint && (f < 0 || f >= math.MaxUint64 ||
+			z.OverflowUint(uint64(f))):
+			err = errRange
+		}
+	}
+	if err != nil {
+		return reflect.Zero(t), true, err
+	}
+
+	return v.Convert(t), true, nil
+}
+
+// numeric returns reflect.Int, reflect.Uint or reflect.Float64 for the kinds
+// of each, otherwise 0
+func numeric(k reflect.Kind) reflect.Kind {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+
+	return 0
+}
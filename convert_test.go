@@ -0,0 +1,248 @@
+package gofe
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+type Colour string
+
+type Bytes []byte
+
+func TestConvertBasicKinds(t *testing.T) {
+	n := 7
+
+	for _, v := range []struct {
+		s string
+		v interface{}
+	}{
+		{"hello", "hello"},
+		{"red", Colour("red")},
+		{"true", true},
+		{"-42", -42},
+		{"-8", int8(-8)},
+		{"0x10", int64(16)},
+		{"42", uint(42)},
+		{"255", uint8(255)},
+		{"1.5", float32(1.5)},
+		{"-2.25", -2.25},
+		{"(1+2i)", complex(1, 2)},
+		{"abc", []byte("abc")},
+		{"abc", Bytes("abc")},
+		{"7", &n},
+		{"any", interface{}("any")},
+	} {
+		p, err := convert(v.s, reflect.TypeOf(v.v))
+
+		assert.True(t, err == nil)
+		assert.Equal(t, v.v, p.Interface())
+	}
+}
+
+func TestConvertDurationsTimesAndTextUnmarshalers(t *testing.T) {
+	p, err := convert("1h30m", reflect.TypeOf(time.Duration(0)))
+	assert.True(t, err == nil)
+	assert.Equal(t, 90*time.Minute, p.Interface())
+
+	p, err = convert("2014-01-02T15:04:05Z", reflect.TypeOf(time.Time{}))
+	assert.True(t, err == nil)
+	assert.Equal(t, time.Date(2014, 1, 2, 15, 4, 5, 0, time.UTC), p.Interface())
+
+	p, err = convert("2014-01-02T15:04:05Z", reflect.TypeOf(&time.Time{}))
+	assert.True(t, err == nil)
+	assert.Equal(t, time.Date(2014, 1, 2, 15, 4, 5, 0, time.UTC),
+		*p.Interface().(*time.Time))
+
+	p, err = convert("127.0.0.1", reflect.TypeOf(net.IP{}))
+	assert.True(t, err == nil)
+	assert.Equal(t, "127.0.0.1", p.Interface().(net.IP).String())
+}
+
+func TestConvertErrors(t *testing.T) {
+	for _, v := range []struct {
+		s   string
+		v   interface{}
+		err string
+	}{
+		{"yes", false, `strconv.ParseBool: parsing "yes": invalid syntax`},
+		{"300", int8(0), `strconv.ParseInt: parsing "300": value out of range`},
+		{"-1", uint(0), `strconv.ParseUint: parsing "-1": invalid syntax`},
+		{"soon", time.Duration(0), `time: invalid duration "soon"`},
+		{"x", []int{}, `cannot convert "x" to []int`},
+		{"x", struct{}{}, `cannot convert "x" to struct {}`},
+	} {
+		typ := reflect.TypeOf(v.v)
+
+		p, err := convert(v.s, typ)
+
+		assert.Equal(t, v.err, err.Error())
+		assert.Equal(t, reflect.Zero(typ).Interface(), p.Interface())
+	}
+}
+
+func TestStepsConvertArgs(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Add(`^(\w+) is (\w+) for (\S+) at (\S+)$`,
+		func(t Testing) func(Colour, bool, time.Duration, time.Time) {
+			return func(c Colour, ok bool, d time.Duration, at time.Time) {
+				got = append(got, c, ok, d, at)
+			}
+		})
+
+	fe := New(tT, s)
+	fe.Step("red is true for 5s at 2014-01-02T15:04:05Z")
+
+	assert.Equal(t, []interface{}{
+		Colour("red"),
+		true,
+		5 * time.Second,
+		time.Date(2014, 1, 2, 15, 4, 5, 0, time.UTC),
+	}, got)
+}
+
+type Money struct {
+	Cents int
+}
+
+func parseMoney(s string) (Money, error) {
+	var d, c int
+	_, err := fmt.Sscanf(s, "$%d.%02d", &d, &c)
+
+	return Money{d*100 + c}, err
+}
+
+func TestStepsConvertRegisteredTypes(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Add(`^I pay (\S+) by (\w+)$`, func(t Testing) func(Money, Colour) {
+		return func(m Money, c Colour) {
+			got = append(got, m, c)
+		}
+	})
+	s.Convert(parseMoney)
+	s.Convert(func(s string) (Colour, error) {
+		return Colour(strings.ToUpper(s)), nil
+	})
+
+	fe := New(tT, s)
+	fe.Step("I pay $4.20 by card")
+
+	assert.Equal(t, []interface{}{Money{420}, Colour("CARD")}, got)
+}
+
+func TestStepsConvertOnlyTheirOwnSteps(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	a, b := NewSteps(), NewSteps()
+	a.Convert(func(s string) (Colour, error) {
+		return Colour(strings.ToUpper(s)), nil
+	})
+	b.Add(`^(\w+) is my colour$`, func(t Testing) func(Colour) {
+		return func(c Colour) {
+			got = append(got, c)
+		}
+	})
+
+	fe := New(tT, a, b)
+	fe.Step("red is my colour")
+
+	assert.Equal(t, []interface{}{Colour("red")}, got)
+}
+
+type Cents int
+
+func TestStepsConvertExpressionParameters(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.AddExpr("I pay {int} to {string}", func(t Testing) func(Cents, string) {
+		return func(c Cents, name string) {
+			got = append(got, c, name)
+		}
+	})
+	s.Convert(func(s string) (Cents, error) {
+		d, err := strconv.Atoi(s)
+
+		return Cents(d * 100), err
+	})
+	s.Convert(func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+
+	fe := New(tT, s)
+	fe.Step(`I pay 5 to "Batman"`)
+
+	assert.Equal(t, []interface{}{Cents(500), "BATMAN"}, got)
+}
+
+func TestConvertMustImplementATransform(t *testing.T) {
+	s := NewSteps()
+	s.Convert(parseMoney)
+
+	for _, v := range []interface{}{
+		nil,
+		"string",
+		func(s string) Money { return Money{} },
+		func(i int) (Money, error) { return Money{}, nil },
+	} {
+		fn := v
+
+		assert.Panic(t, "converters must implement func(string) (T, error)", func() {
+			s.Convert(fn)
+		})
+	}
+
+	assert.Panic(t, "converter for gofe.Money already exists", func() {
+		s.Convert(parseMoney)
+	})
+}
+
+func TestConversionErrorsFailTheStep(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran bool
+
+	s := NewSteps()
+	s.Add(`^I have (\w+) cukes$`, func(t Testing) func(int) {
+		return func(int) {
+			ran = true
+		}
+	})
+	s.Add(`^(\w+) pays (\S+)$`, func(t Testing) func(*Step, string, Money) {
+		return func(*Step, string, Money) {
+			ran = true
+		}
+	})
+	s.Convert(parseMoney)
+
+	fe := New(tT, s)
+	fe.Step("I have abc cukes")
+	fe.Step("Batman pays 5")
+
+	assert.Equal(t, false, ran)
+	assert.Equal(t, Failed, fe.Status())
+	assert.Equal(t, []string{
+		"`I have abc cukes`: argument 1: \"abc\" as int: " +
+			"strconv.ParseInt: parsing \"abc\": invalid syntax",
+		"`Batman pays 5`: argument 3: \"5\" as gofe.Money: " +
+			"input does not match format",
+	}, tT.fatalfs)
+}
@@ -0,0 +1,22 @@
+package gofe
+
+// DocString is a doc string step argument. Like a Table, a step func can
+// declare a DocString as its last argument and will be given either the
+// step's Gherkin doc string or a DocString passed explicitly as the last
+// argument of the step.
+//
+//		Given the request body:
+//		  """json
+//		  {"name": "Batman"}
+//		  """
+//
+// MediaType is the optional content type given after the opening delimiter,
+// eg. json.
+type DocString struct {
+	Content   string
+	MediaType string
+}
+
+func (d DocString) String() string {
+	return d.Content
+}
@@ -0,0 +1,358 @@
+package gofe
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// parameterType is a named {parameter} of a Cucumber Expression. transform
+// converts the matched text before it is converted to the step func's
+// argument type.
+type parameterType struct {
+	name      string
+	regexp    string
+	transform func(string) (interface{}, error)
+}
+
+var parameterTypes = map[string]*parameterType{
+	"int": {
+		name:   "int",
+		regexp: `-?\d+`,
+		transform: func(s string) (interface{}, error) {
+			return strconv.ParseInt(s, 10, 64)
+		},
+	},
+	"float": {
+		name:   "float",
+		regexp: `[-+]?\d*\.?\d+(?:[eE][-+]?\d+)?`,
+		transform: func(s string) (interface{}, error) {
+			return strconv.ParseFloat(s, 64)
+		},
+	},
+	"word": {
+		name:   "word",
+		regexp: `[^\s]+`,
+	},
+	"string": {
+		name:   "string",
+		regexp: `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+		transform: func(s string) (interface{}, error) {
+			q := s[:1]
+
+			return strings.Replace(s[1:len(s)-1], `\`+q, q, -1), nil
+		},
+	},
+	"": {
+		name:   "",
+		regexp: `.*`,
+	},
+}
+
+var errType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ParameterType defines a {name} parameter for the Steps' Cucumber Expressions,
+// matching the regex reg. fn must implement func(string) (T, error) and
+// converts the matched text to the value passed to the StepFunc, a nil fn
+// passes the matched text as is. Like Add it always returns nil.
+//
+//		s.ParameterType("user", `[A-Z]\w+`, func(name string) (*User, error) {
+//			return fixtures.User(name)
+//		})
+//		s.AddExpr("{user} is logged in", func(t Testing) func(*User) {
+//			...
+//		})
+//
+// Parameter types must be defined before any expression using them is added.
+func (s Steps) ParameterType(name, reg string, fn interface{}) interface{} {
+	s.mustBeNew()
+
+	if _, ok := parameterTypes[name]; ok || s.params[name] != nil {
+		panic(fmt.Sprintf("parameter type {%s} already exists", name))
+	}
+	if strings.ContainsAny(name, "{}()\\/") {
+		panic(fmt.Sprintf("parameter type {%s} has an invalid name", name))
+	}
+
+	re, err := uncapture(reg)
+	if err != nil {
+		panic(err)
+	}
+
+	p := &parameterType{
+		name:   name,
+		regexp: re,
+	}
+
+	if fn != nil {
+		p.transform, err = parameterTransform(fn)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	s.params[name] = p
+
+	return nil
+}
+
+// parameterTransform wraps a func(string) (T, error)
+func parameterTransform(fn interface{}) (func(string) (interface{}, error), error) {
+	v := reflect.ValueOf(fn)
+	if !isTransform(v.Type()) {
+		return nil, fmt.Errorf("parameter types must implement " +
+			"func(string) (T, error)")
+	}
+
+	return transform(v), nil
+}
+
+// isTransform checks if t implements func(string) (T, error)
+func isTransform(t reflect.Type) bool {
+	return t.Kind() == reflect.Func &&
+		t.NumIn() == 1 && t.In(0).Kind() == reflect.String &&
+		t.NumOut() == 2 && t.Out(1) == errType
+}
+
+// transform wraps v, a func(string) (T, error)
+func transform(v reflect.Value) func(string) (interface{}, error) {
+	t := v.Type()
+
+	return func(s string) (interface{}, error) {
+		out := v.Call([]reflect.Value{
+			reflect.ValueOf(s).Convert(t.In(0)),
+		})
+
+		err, _ := out[1].Interface().(error)
+
+		return out[0].Interface(), err
+	}
+}
+
+// uncapture rewrites any capture groups in reg as non capturing, so each
+// parameter remains a single capture group
+func uncapture(reg string) (string, error) {
+	re, err := syntax.Parse(reg, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+
+	return uncaptureSyntax(re).String(), nil
+}
+
+func uncaptureSyntax(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	for i, v := range re.Sub {
+		re.Sub[i] = uncaptureSyntax(v)
+	}
+
+	return re
+}
+
+type exprTokenKind int
+
+const (
+	exprText exprTokenKind = iota
+	exprSpace
+	exprOptional
+	exprParam
+	exprAlternation
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	v    string
+}
+
+// compileExpr compiles a Cucumber Expression into an anchored regular
+// expression, returning the parameter type of each capture group.
+//
+//		I have {int} cucumber(s) in my belly/stomach
+//
+// Supports {parameter} types, (optional) text and / alternation of words, a \
+// escapes any of those special characters. types are any parameter types
+// defined in addition to the built in types.
+func compileExpr(expr string,
+	types map[string]*parameterType) (*regexp.Regexp, []*parameterType, error) {
+
+	toks, err := tokenizeExpr(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b bytes.Buffer
+	var params []*parameterType
+
+	b.WriteString("^")
+
+	// alternation is bound by whitespace, so write out word by word
+	var word []exprToken
+	for i := 0; i <= len(toks); i++ {
+		if i < len(toks) && toks[i].kind != exprSpace {
+			word = append(word, toks[i])
+
+			continue
+		}
+
+		p, err := writeExprWord(&b, expr, word, types)
+		if err != nil {
+			return nil, nil, err
+		}
+		params = append(params, p...)
+
+		if i < len(toks) {
+			b.WriteString(regexp.QuoteMeta(toks[i].v))
+		}
+		word = word[:0]
+	}
+
+	b.WriteString("$")
+
+	reg, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reg, params, nil
+}
+
+// tokenizeExpr splits an expression into text, whitespace, optional text,
+// parameters and alternation
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var toks []exprToken
+	var text []rune
+
+	flush := func() {
+		if len(text) > 0 {
+			toks = append(toks, exprToken{exprText, string(text)})
+			text = nil
+		}
+	}
+
+	r := []rune(expr)
+	for i := 0; i < len(r); i++ {
+		switch c := r[i]; {
+		case c == '\\':
+			if i++; i == len(r) {
+				return nil, fmt.Errorf("`%s`: expression ends with an escape", expr)
+			}
+			text = append(text, r[i])
+
+		case unicode.IsSpace(c):
+			flush()
+			toks = append(toks, exprToken{exprSpace, string(c)})
+
+		case c == '/':
+			flush()
+			toks = append(toks, exprToken{exprAlternation, "/"})
+
+		case c == '(' || c == '{':
+			end := ')'
+			kind := exprOptional
+			if c == '{' {
+				end = '}'
+				kind = exprParam
+			}
+
+			var v []rune
+			for i++; i < len(r) && r[i] != end; i++ {
+				switch r[i] {
+				case '(', '{':
+					return nil, fmt.Errorf("`%s`: %c may not be nested within %c",
+						expr, r[i], c)
+				case '\\':
+					if i+1 < len(r) {
+						i++
+					}
+				}
+
+				v = append(v, r[i])
+			}
+			if i == len(r) {
+				return nil, fmt.Errorf("`%s`: missing %c", expr, end)
+			}
+			if kind == exprOptional && len(v) == 0 {
+				return nil, fmt.Errorf("`%s`: optional text may not be empty", expr)
+			}
+
+			flush()
+			toks = append(toks, exprToken{kind, string(v)})
+
+		default:
+			text = append(text, c)
+		}
+	}
+	flush()
+
+	return toks, nil
+}
+
+// writeExprWord writes a whitespace delimited word, returning the parameter
+// types of any capture groups written
+func writeExprWord(b *bytes.Buffer,
+	expr string,
+	word []exprToken,
+	types map[string]*parameterType) ([]*parameterType, error) {
+
+	var alts [][]exprToken
+	var alt []exprToken
+	for _, v := range word {
+		if v.kind == exprAlternation {
+			alts = append(alts, alt)
+			alt = nil
+
+			continue
+		}
+
+		alt = append(alt, v)
+	}
+	alts = append(alts, alt)
+
+	var params []*parameterType
+	if len(alts) > 1 {
+		b.WriteString("(?:")
+	}
+	for i, alt := range alts {
+		if len(alts) > 1 && len(alt) == 0 {
+			return nil, fmt.Errorf("`%s`: alternative may not be empty", expr)
+		}
+		if i > 0 {
+			b.WriteString("|")
+		}
+
+		for _, v := range alt {
+			switch v.kind {
+			case exprText:
+				b.WriteString(regexp.QuoteMeta(v.v))
+
+			case exprOptional:
+				fmt.Fprintf(b, "(?:%s)?", regexp.QuoteMeta(v.v))
+
+			case exprParam:
+				p, ok := types[v.v]
+				if !ok {
+					p, ok = parameterTypes[v.v]
+				}
+				if !ok {
+					return nil, fmt.Errorf("`%s`: undefined parameter type {%s}",
+						expr, v.v)
+				}
+
+				fmt.Fprintf(b, "(%s)", p.regexp)
+				params = append(params, p)
+			}
+		}
+	}
+	if len(alts) > 1 {
+		b.WriteString(")")
+	}
+
+	return params, nil
+}
@@ -0,0 +1,261 @@
+package gofe
+
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+func TestCompileExpr(t *testing.T) {
+	for _, v := range []struct {
+		expr, reg string
+		params    []string
+	}{
+		{"a + b = 4", `^a \+ b = 4$`, nil},
+		{"I have {int} cuke(s)", `^I have (-?\d+) cuke(?:s)?$`, []string{"int"}},
+		{"it is/are {word}", `^it (?:is|are) ([^\s]+)$`, []string{"word"}},
+		{"{} or {string}", `^(.*) or ("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')$`,
+			[]string{"", "string"}},
+		{`\(not optional\) \{not\} a\/b`, `^\(not optional\) \{not\} a/b$`, nil},
+		{"a/b(s)/c", `^(?:a|b(?:s)?|c)$`, nil},
+	} {
+		reg, params, err := compileExpr(v.expr, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, p := range params {
+			names = append(names, p.name)
+		}
+
+		assert.Equal(t, v.reg, reg.String(), v.expr)
+		assert.Equal(t, v.params, names, v.expr)
+	}
+}
+
+func TestCompileExprErrors(t *testing.T) {
+	for _, v := range []struct {
+		expr, err string
+	}{
+		{"a {nope}", "`a {nope}`: undefined parameter type {nope}"},
+		{"a {int", "`a {int`: missing }"},
+		{"a (s", "`a (s`: missing )"},
+		{"a ()", "`a ()`: optional text may not be empty"},
+		{"a ({int})", "`a ({int})`: { may not be nested within ("},
+		{"a / b", "`a / b`: alternative may not be empty"},
+		{`a \`, "`a \\`: expression ends with an escape"},
+	} {
+		_, _, err := compileExpr(v.expr, nil)
+		if err == nil {
+			t.Fatalf("expected error for %q", v.expr)
+		}
+
+		assert.Equal(t, v.err, err.Error())
+	}
+}
+
+func TestAddExprPassesParametersAsArgs(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.AddExpr("{int} + {int} = {float}", func(t Testing) func(int, int8, float32) {
+		return func(a int, b int8, c float32) {
+			t.Logf("%d + %d = %.1f", a, b, c)
+		}
+	})
+	s.AddExpr("I have {int} cucumber(s) in my belly/stomach",
+		func(t Testing) func(string) {
+			return func(n string) {
+				t.Logf("%s cucumbers", n)
+			}
+		})
+	s.AddExpr("{word} said {string}", func(t Testing) func(string, string) {
+		return func(who, what string) {
+			t.Logf("%s: %s", who, what)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("1 + -2 = -1.5")
+	fe.Step("I have 1 cucumber in my belly")
+	fe.Step("I have 42 cucumbers in my stomach")
+	fe.Step(`Batman said "I'm \"Batman\""`)
+	fe.Step(`Robin said 'Holy \'moly\''`)
+
+	for i, v := range []string{
+		"1 + -2 = -1.5",
+		"1 cucumbers",
+		"42 cucumbers",
+		`Batman: I'm "Batman"`,
+		"Robin: Holy 'moly'",
+	} {
+		assert.Equal(t, v, tT.logfs[i], v)
+	}
+}
+
+func TestAddExprReportsOutOfRangeAndInvalidParameters(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []interface{}
+
+	s := NewSteps()
+	s.AddExpr("I have {int} cukes", func(t Testing) func(uint8) {
+		return func(n uint8) {
+			ran = append(ran, n)
+		}
+	})
+	s.AddExpr("I owe {int} cukes", func(t Testing) func(uint) {
+		return func(n uint) {
+			ran = append(ran, n)
+		}
+	})
+	s.AddExpr("I ate {float} cukes", func(t Testing) func(int) {
+		return func(n int) {
+			ran = append(ran, n)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("I have 300 cukes")
+	fe.Step("I owe -1 cukes")
+	fe.Step("I ate 1.5 cukes")
+	fe.Step("I have 255 cukes")
+	fe.Step("I ate 2 cukes")
+
+	assert.Equal(t, []interface{}{uint8(255), 2}, ran)
+	assert.Equal(t, []string{
+		"`I have 300 cukes`: argument 1: \"300\" as uint8: value out of range",
+		"`I owe -1 cukes`: argument 1: \"-1\" as uint: value out of range",
+		"`I ate 1.5 cukes`: argument 1: \"1.5\" as int: not an integer",
+	}, tT.fatalfs)
+}
+
+func TestAddExprParsesIntsAsDecimal(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.AddExpr("I wait {int} minutes", func(t Testing) func(int) {
+		return func(n int) {
+			got = append(got, n)
+		}
+	})
+	s.AddExpr("I wait {int} hours", func(t Testing) func(uint16) {
+		return func(n uint16) {
+			got = append(got, n)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("I wait 010 minutes")
+	fe.Step("I wait 08 minutes")
+	fe.Step("I wait 09 hours")
+
+	assert.Equal(t, []interface{}{10, 8, uint16(9)}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestAddExprPanicsOnInvalidExpressions(t *testing.T) {
+	s := NewSteps()
+	assert.Panic(t, "`a {nope}`: undefined parameter type {nope}", func() {
+		s.AddExpr("a {nope}", func(t Testing) func() {
+			return func() {}
+		})
+	})
+}
+
+type cents int64
+
+func TestParameterTypesConvertMatchedText(t *testing.T) {
+	tT := &tTesting{}
+
+	type User struct {
+		Name string
+	}
+
+	fixtures := map[string]*User{
+		"Batman": {"Batman"},
+	}
+
+	s := NewSteps()
+	s.ParameterType("user", `[A-Z]\w+`, func(name string) (*User, error) {
+		return fixtures[name], nil
+	})
+	s.ParameterType("money", `\$(\d+)\.(\d\d)`, func(s string) (cents, error) {
+		var d, c int64
+		_, err := fmt.Sscanf(s, "$%d.%d", &d, &c)
+
+		return cents(d*100 + c), err
+	})
+	s.ParameterType("color", `red|green`, nil)
+	s.AddExpr("{user} pays {money} for {int} {color} item(s)",
+		func(t Testing) func(*User, cents, int, string) {
+			return func(u *User, c cents, n int, color string) {
+				t.Logf("%s paid %d cents for %d %s", u.Name, c, n, color)
+			}
+		})
+
+	fe := New(tT, s)
+	fe.Step("Batman pays $12.50 for 2 red items")
+
+	assert.Equal(t, "Batman paid 1250 cents for 2 red", tT.logfs[0])
+}
+
+func TestParameterTypesCanTransformToNil(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.ParameterType("thing", `\w+`, func(string) (fmt.Stringer, error) {
+		return nil, nil
+	})
+	s.AddExpr("a {thing} and {thing}", func(t Testing) func(fmt.Stringer, *int) {
+		return func(a fmt.Stringer, b *int) {
+			got = append(got, a, b)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a this and that")
+
+	assert.Equal(t, []interface{}{nil, (*int)(nil)}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestParameterTypesMustBeValid(t *testing.T) {
+	s := NewSteps()
+	s.ParameterType("user", `\w+`, nil)
+
+	for _, v := range []struct {
+		name, reg string
+		fn        interface{}
+		err       string
+	}{
+		{"int", `\d+`, nil, "parameter type {int} already exists"},
+		{"user", `\w+`, nil, "parameter type {user} already exists"},
+		{"a/b", `\w+`, nil, "parameter type {a/b} has an invalid name"},
+		{"a", `\w+`, func(int) (int, error) { return 0, nil },
+			"parameter types must implement func(string) (T, error)"},
+		{"a", `\w+`, func(string) int { return 0 },
+			"parameter types must implement func(string) (T, error)"},
+		{"a", `(\w+`, nil,
+			"error parsing regexp: missing closing ): `(\\w+`"},
+	} {
+		assert.Panic(t, v.err, func() {
+			s.ParameterType(v.name, v.reg, v.fn)
+		}, v.name)
+	}
+}
+
+func TestUncapture(t *testing.T) {
+	re, err := uncapture(`(a)|(?:b(c))|(?P<d>d)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `a|bc|d`, re)
+}
@@ -0,0 +1,652 @@
+package gofe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Location is a 1 based line and column within a feature file
+type Location struct {
+	Line   int
+	Column int
+}
+
+// Comment is a # comment line
+type Comment struct {
+	Location Location
+	Text     string
+}
+
+// Tag is a single @tag, Name includes the leading @
+type Tag struct {
+	Location Location
+	Name     string
+}
+
+// Document is the parsed representation of a single .feature file. Feature is
+// nil if the file contained no Feature, eg. only comments.
+type Document struct {
+	Path     string
+	Feature  *FeatureNode
+	Comments []*Comment
+}
+
+// FeatureNode is a Gherkin Feature. Scenarios are only those defined before
+// any Rule.
+type FeatureNode struct {
+	Location    Location
+	Tags        []*Tag
+	Keyword     string
+	Name        string
+	Description string
+	Background  *BackgroundNode
+	Scenarios   []*ScenarioNode
+	Rules       []*RuleNode
+}
+
+// RuleNode is a Gherkin Rule, grouping the Scenarios defined after it. A
+// Rule's Background is run after the Feature's Background.
+type RuleNode struct {
+	Location    Location
+	Tags        []*Tag
+	Keyword     string
+	Name        string
+	Description string
+	Background  *BackgroundNode
+	Scenarios   []*ScenarioNode
+}
+
+// BackgroundNode is a Gherkin Background
+type BackgroundNode struct {
+	Location    Location
+	Keyword     string
+	Name        string
+	Description string
+	Steps       []*StepNode
+}
+
+// ScenarioNode is a Gherkin Scenario (or Example). A Scenario Outline (or
+// Scenario Template) is also a ScenarioNode, whose steps are expanded for each
+// row of its Examples.
+type ScenarioNode struct {
+	Location    Location
+	Tags        []*Tag
+	Keyword     string
+	Name        string
+	Description string
+	Steps       []*StepNode
+	Examples    []*ExamplesNode
+}
+
+// Outline returns true if the Scenario is a Scenario Outline
+func (s *ScenarioNode) Outline() bool {
+	return s.Keyword == "Scenario Outline" || s.Keyword == "Scenario Template"
+}
+
+// ExamplesNode is an Examples (or Scenarios) table of a Scenario Outline. The
+// first row of Rows is the header.
+type ExamplesNode struct {
+	Location    Location
+	Tags        []*Tag
+	Keyword     string
+	Name        string
+	Description string
+	Rows        []*TableRow
+}
+
+// TableRow is a single | delimited row of a table
+type TableRow struct {
+	Location Location
+	Cells    []*TableCell
+}
+
+// Values returns the value of each cell in the row
+func (r *TableRow) Values() []string {
+	v := make([]string, len(r.Cells))
+	for i, c := range r.Cells {
+		v[i] = c.Value
+	}
+
+	return v
+}
+
+// TableCell is a single cell of a TableRow, with any escapes (\|, \n and \\)
+// already unescaped
+type TableCell struct {
+	Location Location
+	Value    string
+}
+
+// StepNode is a single Given, When, Then, And, But or * step. Keyword does not
+// include the trailing space.
+type StepNode struct {
+	Location  Location
+	Keyword   string
+	Text      string
+	DataTable []*TableRow
+	DocString *DocStringNode
+}
+
+// DocStringNode is a step's """ or ``` delimited doc string. Content has the
+// indentation of the opening delimiter removed from each line.
+type DocStringNode struct {
+	Location  Location
+	Delimiter string
+	MediaType string
+	Content   string
+}
+
+// SyntaxError is returned for any malformed feature file
+type SyntaxError struct {
+	Path     string
+	Location Location
+	Msg      string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s",
+		e.Path, e.Location.Line, e.Location.Column, e.Msg)
+}
+
+const danglingTags = "tags must be followed by a Feature, Rule, Scenario or " +
+	"Examples"
+
+var stepKeywords = []string{
+	"Given ",
+	"When ",
+	"Then ",
+	"And ",
+	"But ",
+	"* ",
+}
+
+var headerKeywords = []string{
+	"Feature",
+	"Rule",
+	"Background",
+	"Scenario Outline",
+	"Scenario Template",
+	"Scenario",
+	"Examples",
+	"Example",
+	"Scenarios",
+}
+
+// parser is a line based state machine, building up a Document as each line is
+// read
+type parser struct {
+	path string
+	doc  *Document
+	loc  Location
+
+	tags     []*Tag
+	rule     *RuleNode
+	scenario *ScenarioNode
+	steps    *[]*StepNode
+	rows     *[]*TableRow
+
+	// arg is the last step, while it can still be given a doc string
+	arg *StepNode
+
+	// docString is the currently open doc string and its lines
+	docString *DocStringNode
+	docLines  []string
+
+	// desc is the Description currently being collected, nil once any steps
+	// have been defined
+	desc      *string
+	descLines []string
+}
+
+// ParseFeature parses a Gherkin document read from r. path is only used to
+// annotate the Document and any SyntaxError.
+func ParseFeature(path string, r io.Reader) (*Document, error) {
+	p := &parser{
+		path: path,
+		doc: &Document{
+			Path: path,
+		},
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		p.loc.Line++
+
+		line := sc.Text()
+		if p.loc.Line == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		err := p.parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := p.end(); err != nil {
+		return nil, err
+	}
+
+	return p.doc, nil
+}
+
+// ParseFeatureFile opens and parses a .feature file
+func ParseFeatureFile(path string) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseFeature(path, f)
+}
+
+func (p *parser) errorf(f string, v ...interface{}) error {
+	return &SyntaxError{
+		Path:     p.path,
+		Location: p.loc,
+		Msg:      fmt.Sprintf(f, v...),
+	}
+}
+
+// column returns the 1 based column of the first non space character
+func column(line string) int {
+	i := len(line) - len(strings.TrimLeft(line, " \t"))
+
+	return utf8.RuneCountInString(line[:i]) + 1
+}
+
+// header returns the keyword and name of a `Keyword: name` line
+func header(s string) (string, string, bool) {
+	for _, k := range headerKeywords {
+		if strings.HasPrefix(s, k+":") {
+			return k, strings.TrimSpace(s[len(k)+1:]), true
+		}
+	}
+
+	return "", "", false
+}
+
+// stepKeyword returns the keyword and text of a step line, the text is empty
+// for a keyword alone
+func stepKeyword(s string) (string, string, bool) {
+	for _, k := range stepKeywords {
+		if strings.HasPrefix(s, k) {
+			return strings.TrimSpace(k), strings.TrimSpace(s[len(k):]), true
+		}
+		if s == strings.TrimSpace(k) {
+			return s, "", true
+		}
+	}
+
+	return "", "", false
+}
+
+func (p *parser) parseLine(line string) error {
+	if p.docString != nil {
+		return p.parseDocStringLine(line)
+	}
+
+	s := strings.TrimSpace(line)
+	p.loc.Column = column(line)
+
+	if strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "```") {
+		return p.parseDocString(s)
+	}
+
+	switch {
+	case s == "":
+		if p.desc != nil {
+			p.descLines = append(p.descLines, "")
+		}
+
+		return nil
+
+	case strings.HasPrefix(s, "#"):
+		p.doc.Comments = append(p.doc.Comments, &Comment{
+			Location: p.loc,
+			Text:     s,
+		})
+
+		return nil
+
+	case strings.HasPrefix(s, "@"):
+		return p.parseTags(line)
+
+	case strings.HasPrefix(s, "|"):
+		return p.parseRow(line)
+	}
+
+	if k, name, ok := header(s); ok {
+		p.endDescription()
+
+		return p.parseHeader(k, name)
+	}
+
+	if k, text, ok := stepKeyword(s); ok {
+		return p.parseStep(k, text)
+	}
+
+	if p.desc == nil {
+		return p.errorf("unexpected text %q", s)
+	}
+	p.descLines = append(p.descLines, s)
+
+	return nil
+}
+
+func (p *parser) parseTags(line string) error {
+	p.endDescription()
+
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+
+			continue
+		}
+
+		j := i
+		for j < len(line) && line[j] != ' ' && line[j] != '\t' {
+			j++
+		}
+
+		f := line[i:j]
+		if strings.HasPrefix(f, "#") {
+			break // trailing comment
+		}
+
+		p.loc.Column = utf8.RuneCountInString(line[:i]) + 1
+		if !strings.HasPrefix(f, "@") || len(f) == 1 {
+			return p.errorf("invalid tag %q", f)
+		}
+
+		p.tags = append(p.tags, &Tag{
+			Location: p.loc,
+			Name:     f,
+		})
+
+		i = j
+	}
+
+	return nil
+}
+
+func (p *parser) parseHeader(k, name string) error {
+	tags := p.tags
+	p.tags = nil
+
+	f := p.doc.Feature
+	if f == nil && k != "Feature" {
+		return p.errorf("expected Feature, got %q", k+":")
+	}
+
+	switch k {
+	case "Feature":
+		if f != nil {
+			return p.errorf("multiple Features defined")
+		}
+
+		f = &FeatureNode{
+			Location: p.loc,
+			Tags:     tags,
+			Keyword:  k,
+			Name:     name,
+		}
+		p.doc.Feature = f
+		p.describe(&f.Description, nil)
+
+	case "Rule":
+		r := &RuleNode{
+			Location: p.loc,
+			Tags:     tags,
+			Keyword:  k,
+			Name:     name,
+		}
+		f.Rules = append(f.Rules, r)
+		p.describe(&r.Description, nil)
+		p.rule = r
+		p.scenario = nil
+
+	case "Background":
+		if len(tags) > 0 {
+			return p.errorf("tags are not allowed on a Background")
+		}
+
+		bg, scs := &f.Background, len(f.Scenarios)+len(f.Rules)
+		if r := p.rule; r != nil {
+			bg, scs = &r.Background, len(r.Scenarios)
+		}
+		if *bg != nil {
+			return p.errorf("multiple Backgrounds defined")
+		}
+		if scs > 0 {
+			return p.errorf("Background must be defined before any Scenario")
+		}
+
+		b := &BackgroundNode{
+			Location: p.loc,
+			Keyword:  k,
+			Name:     name,
+		}
+		*bg = b
+		p.describe(&b.Description, &b.Steps)
+		p.scenario = nil
+
+	case "Examples", "Scenarios":
+		sc := p.scenario
+		if sc == nil || !sc.Outline() {
+			return p.errorf("%s must follow a Scenario Outline", k)
+		}
+
+		ex := &ExamplesNode{
+			Location: p.loc,
+			Tags:     tags,
+			Keyword:  k,
+			Name:     name,
+		}
+		sc.Examples = append(sc.Examples, ex)
+		p.describe(&ex.Description, nil)
+		p.rows = &ex.Rows
+
+	default:
+		sc := &ScenarioNode{
+			Location: p.loc,
+			Tags:     tags,
+			Keyword:  k,
+			Name:     name,
+		}
+		if r := p.rule; r != nil {
+			r.Scenarios = append(r.Scenarios, sc)
+		} else {
+			f.Scenarios = append(f.Scenarios, sc)
+		}
+		p.describe(&sc.Description, &sc.Steps)
+		p.scenario = sc
+	}
+
+	return nil
+}
+
+func (p *parser) parseStep(k, text string) error {
+	if text == "" {
+		return p.errorf("empty step %q", k)
+	}
+	if len(p.tags) > 0 {
+		return p.errorf(danglingTags)
+	}
+	if p.steps == nil {
+		return p.errorf("unexpected step %q, steps must be within a Scenario "+
+			"or Background", k+" "+text)
+	}
+	p.endDescription()
+
+	st := &StepNode{
+		Location: p.loc,
+		Keyword:  k,
+		Text:     text,
+	}
+	*p.steps = append(*p.steps, st)
+
+	// any following rows are the step's data table, or its doc string
+	p.rows = &st.DataTable
+	p.arg = st
+
+	return nil
+}
+
+func (p *parser) parseRow(line string) error {
+	if len(p.tags) > 0 {
+		return p.errorf(danglingTags)
+	}
+	if p.rows == nil {
+		return p.errorf("unexpected table row")
+	}
+	p.endDescription()
+
+	cells, err := p.parseCells(line)
+	if err != nil {
+		return err
+	}
+
+	rows := *p.rows
+	if len(rows) > 0 && len(rows[0].Cells) != len(cells) {
+		return p.errorf("inconsistent cell count, expected %d cells, got %d",
+			len(rows[0].Cells), len(cells))
+	}
+
+	*p.rows = append(rows, &TableRow{
+		Location: p.loc,
+		Cells:    cells,
+	})
+	p.arg = nil
+
+	return nil
+}
+
+// parseCells splits a | delimited row into its cells
+func (p *parser) parseCells(line string) ([]*TableCell, error) {
+	var cells []*TableCell
+
+	start := strings.Index(line, "|") + 1
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++ // skip the escaped character
+
+		case '|':
+			cell := line[start:i]
+			n := len(cell) - len(strings.TrimLeft(cell, " \t"))
+
+			cells = append(cells, &TableCell{
+				Location: Location{
+					Line:   p.loc.Line,
+					Column: utf8.RuneCountInString(line[:start+n]) + 1,
+				},
+				Value: cellUnescaper.Replace(strings.TrimSpace(cell)),
+			})
+
+			start = i + 1
+		}
+	}
+
+	if strings.TrimSpace(line[start:]) != "" {
+		return nil, p.errorf("table row must end with |")
+	}
+
+	return cells, nil
+}
+
+var cellUnescaper = strings.NewReplacer(`\|`, "|", `\n`, "\n", `\\`, `\`)
+
+func (p *parser) parseDocString(s string) error {
+	if p.arg == nil {
+		return p.errorf("unexpected doc string, doc strings must follow a step")
+	}
+
+	d := s[:3]
+	p.docString = &DocStringNode{
+		Location:  p.loc,
+		Delimiter: d,
+		MediaType: strings.TrimSpace(s[3:]),
+	}
+	p.docLines = nil
+
+	return nil
+}
+
+func (p *parser) parseDocStringLine(line string) error {
+	d := p.docString
+	if strings.TrimSpace(line) == d.Delimiter {
+		// unescape any \"\"\" or \`\`\` within the content
+		c := d.Delimiter[:1]
+		esc := strings.Replace(d.Delimiter, c, `\`+c, -1)
+
+		d.Content = strings.Replace(strings.Join(p.docLines, "\n"), esc,
+			d.Delimiter, -1)
+		p.arg.DocString = d
+
+		p.docString = nil
+		p.arg = nil
+		p.rows = nil
+
+		return nil
+	}
+
+	// remove up to the opening delimiter's indentation
+	for i := 1; i < d.Location.Column && line != ""; i++ {
+		if line[0] != ' ' && line[0] != '\t' {
+			break
+		}
+
+		line = line[1:]
+	}
+	p.docLines = append(p.docLines, line)
+
+	return nil
+}
+
+// describe starts collecting a new Description and sets the steps any
+// following steps will be appended to
+func (p *parser) describe(desc *string, steps *[]*StepNode) {
+	p.desc = desc
+	p.descLines = nil
+	p.steps = steps
+	p.rows = nil
+	p.arg = nil
+}
+
+// endDescription assigns any collected description lines. No further
+// description can be given until the next header.
+func (p *parser) endDescription() {
+	if p.desc != nil {
+		*p.desc = strings.TrimSpace(strings.Join(p.descLines, "\n"))
+	}
+
+	p.desc = nil
+	p.descLines = nil
+}
+
+func (p *parser) end() error {
+	if d := p.docString; d != nil {
+		p.loc = d.Location
+
+		return p.errorf("unterminated doc string")
+	}
+
+	p.endDescription()
+
+	if n := len(p.tags); n > 0 {
+		p.loc = p.tags[n-1].Location
+
+		return p.errorf(danglingTags)
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package gofe
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+func parse(t *testing.T, src string) *Document {
+	doc, err := ParseFeature("test.feature", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc
+}
+
+func TestParseFeature(t *testing.T) {
+	doc := parse(t, `# a comment
+@billing @smoke
+Feature: Checkout
+  In order to buy things
+  As a guest
+
+  I want to pay
+
+  Background:
+    Given a cart
+
+  @wip
+  Scenario: Guest pays
+    A description
+
+    Given I have 2 items
+    When I pay # not a comment
+    Then I get a receipt
+    And an email
+    But no account
+    * done
+
+  Example: Another
+    Given something
+`)
+
+	f := doc.Feature
+	assert.Equal(t, "test.feature", doc.Path)
+	assert.Equal(t, 1, len(doc.Comments))
+	assert.Equal(t, "# a comment", doc.Comments[0].Text)
+	assert.Equal(t, Location{1, 1}, doc.Comments[0].Location)
+
+	assert.Equal(t, "Feature", f.Keyword)
+	assert.Equal(t, "Checkout", f.Name)
+	assert.Equal(t, "In order to buy things\nAs a guest\n\nI want to pay",
+		f.Description)
+	assert.Equal(t, Location{3, 1}, f.Location)
+	assert.Equal(t, "@billing", f.Tags[0].Name)
+	assert.Equal(t, Location{2, 1}, f.Tags[0].Location)
+	assert.Equal(t, "@smoke", f.Tags[1].Name)
+	assert.Equal(t, Location{2, 10}, f.Tags[1].Location)
+
+	assert.Equal(t, "", f.Background.Name)
+	assert.Equal(t, Location{9, 3}, f.Background.Location)
+	assert.Equal(t, "a cart", f.Background.Steps[0].Text)
+
+	assert.Equal(t, 2, len(f.Scenarios))
+
+	sc := f.Scenarios[0]
+	assert.Equal(t, "Guest pays", sc.Name)
+	assert.Equal(t, "A description", sc.Description)
+	assert.Equal(t, "@wip", sc.Tags[0].Name)
+	assert.Equal(t, Location{13, 3}, sc.Location)
+
+	for i, v := range [][2]string{
+		{"Given", "I have 2 items"},
+		{"When", "I pay # not a comment"},
+		{"Then", "I get a receipt"},
+		{"And", "an email"},
+		{"But", "no account"},
+		{"*", "done"},
+	} {
+		assert.Equal(t, v[0], sc.Steps[i].Keyword)
+		assert.Equal(t, v[1], sc.Steps[i].Text)
+		assert.Equal(t, Location{16 + i, 5}, sc.Steps[i].Location)
+	}
+
+	assert.Equal(t, "Example", f.Scenarios[1].Keyword)
+	assert.Equal(t, 0, len(f.Scenarios[1].Tags))
+}
+
+func TestParseFeatureWithNoFeature(t *testing.T) {
+	doc := parse(t, "# just a comment\n\n")
+
+	assert.True(t, doc.Feature == nil)
+	assert.Equal(t, 1, len(doc.Comments))
+}
+
+func TestParseFeatureSyntaxErrors(t *testing.T) {
+	for _, v := range []struct {
+		src, err string
+	}{
+		{"Scenario: a", "test.feature:1:1: expected Feature, got \"Scenario:\""},
+		{"Feature: a\nFeature: b", "test.feature:2:1: multiple Features defined"},
+		{"Feature: a\n  Given a", "test.feature:2:3: unexpected step \"Given a\", " +
+			"steps must be within a Scenario or Background"},
+		{"Feature: a\nScenario: b\n  Given a\n  nope",
+			"test.feature:4:3: unexpected text \"nope\""},
+		{"Feature: a\nScenario: b\nBackground:",
+			"test.feature:3:1: Background must be defined before any Scenario"},
+		{"Feature: a\nBackground:\nBackground:",
+			"test.feature:3:1: multiple Backgrounds defined"},
+		{"Feature: a\nRule: b\nBackground:\nBackground:",
+			"test.feature:4:1: multiple Backgrounds defined"},
+		{"Feature: a\nRule: b\nScenario: c\nBackground:",
+			"test.feature:4:1: Background must be defined before any Scenario"},
+		{"Feature: a\n@tag\nBackground:",
+			"test.feature:3:1: tags are not allowed on a Background"},
+		{"Feature: a\n@ok notatag", "test.feature:2:5: invalid tag \"notatag\""},
+		{"Feature: a\nScenario: b\n  Given", "test.feature:3:3: empty step \"Given\""},
+		{"Feature: a\nScenario: b\n  Given a\n    And  ",
+			"test.feature:4:5: empty step \"And\""},
+		{"Feature: a\n  @dangling",
+			"test.feature:2:3: tags must be followed by a Feature, Rule, " +
+				"Scenario or Examples"},
+	} {
+		_, err := ParseFeature("test.feature", strings.NewReader(v.src))
+		if err == nil {
+			t.Fatalf("expected error for %q", v.src)
+		}
+
+		assert.Equal(t, v.err, err.Error())
+	}
+}
+
+func TestParseScenarioOutline(t *testing.T) {
+	doc := parse(t, `Feature: a
+  Scenario Outline: eating
+    Given there are <start> cucumbers
+
+    @big
+    Examples: some
+      Description
+      | start | escaped   |
+      | 12    | a\|b\\c\n |
+      |       | x         |
+`)
+
+	sc := doc.Feature.Scenarios[0]
+	assert.True(t, sc.Outline())
+	assert.Equal(t, 1, len(sc.Examples))
+
+	ex := sc.Examples[0]
+	assert.Equal(t, "Examples", ex.Keyword)
+	assert.Equal(t, "some", ex.Name)
+	assert.Equal(t, "Description", ex.Description)
+	assert.Equal(t, "@big", ex.Tags[0].Name)
+	assert.Equal(t, 3, len(ex.Rows))
+	assert.Equal(t, []string{"start", "escaped"}, ex.Rows[0].Values())
+	assert.Equal(t, []string{"12", "a|b\\c\n"}, ex.Rows[1].Values())
+	assert.Equal(t, []string{"", "x"}, ex.Rows[2].Values())
+	assert.Equal(t, Location{9, 7}, ex.Rows[1].Location)
+	assert.Equal(t, Location{9, 9}, ex.Rows[1].Cells[0].Location)
+	assert.Equal(t, Location{9, 17}, ex.Rows[1].Cells[1].Location)
+}
+
+func TestParseScenarioOutlineSyntaxErrors(t *testing.T) {
+	for _, v := range []struct {
+		src, err string
+	}{
+		{"Feature: a\nScenario: b\nExamples:",
+			"test.feature:3:1: Examples must follow a Scenario Outline"},
+		{"Feature: a\nScenario Outline: b\n  | a |",
+			"test.feature:3:3: unexpected table row"},
+		{"Feature: a\nScenario Outline: b\nExamples:\n  | a | b |\n  | a |",
+			"test.feature:5:3: inconsistent cell count, expected 2 cells, got 1"},
+		{"Feature: a\nScenario Outline: b\nExamples:\n  | a | b",
+			"test.feature:4:3: table row must end with |"},
+		{"Feature: a\nScenario Outline: b\nExamples:\n  | a |\n  Given a",
+			"test.feature:5:3: unexpected step \"Given a\", steps must be " +
+				"within a Scenario or Background"},
+	} {
+		_, err := ParseFeature("test.feature", strings.NewReader(v.src))
+		if err == nil {
+			t.Fatalf("expected error for %q", v.src)
+		}
+
+		assert.Equal(t, v.err, err.Error())
+	}
+}
+
+func TestParseStepDataTable(t *testing.T) {
+	doc := parse(t, `Feature: a
+  Scenario: b
+    Given users:
+      | name   |
+      | Batman |
+    # comment
+      | Robin  |
+    Then done
+`)
+
+	steps := doc.Feature.Scenarios[0].Steps
+	assert.Equal(t, 3, len(steps[0].DataTable))
+	assert.Equal(t, []string{"Robin"}, steps[0].DataTable[2].Values())
+	assert.Equal(t, 0, len(steps[1].DataTable))
+}
+
+func TestParseStepDocString(t *testing.T) {
+	doc := parse(t, `Feature: a
+  Scenario: b
+    Given a body:
+      """json
+      {
+        "name": "Batman"
+      }
+      # not a comment
+      \"\"\"
+      """
+    And some text:
+      `+"```"+`
+    @not a tag
+      `+"```"+`
+`)
+
+	steps := doc.Feature.Scenarios[0].Steps
+
+	d := steps[0].DocString
+	assert.Equal(t, Location{4, 7}, d.Location)
+	assert.Equal(t, `"""`, d.Delimiter)
+	assert.Equal(t, "json", d.MediaType)
+	assert.Equal(t, "{\n  \"name\": \"Batman\"\n}\n# not a comment\n\"\"\"",
+		d.Content)
+
+	d = steps[1].DocString
+	assert.Equal(t, "```", d.Delimiter)
+	assert.Equal(t, "", d.MediaType)
+	assert.Equal(t, "@not a tag", d.Content)
+}
+
+func TestParseStepDocStringSyntaxErrors(t *testing.T) {
+	for _, v := range []struct {
+		src, err string
+	}{
+		{"Feature: a\nScenario: b\n  \"\"\"\n  \"\"\"",
+			"test.feature:3:3: unexpected doc string, doc strings must follow " +
+				"a step"},
+		{"Feature: a\nScenario: b\n  Given a\n  | a |\n  \"\"\"\n  \"\"\"",
+			"test.feature:5:3: unexpected doc string, doc strings must follow " +
+				"a step"},
+		{"Feature: a\nScenario: b\n  Given a\n  \"\"\"\n  \"\"\"\n  | a |",
+			"test.feature:6:3: unexpected table row"},
+		{"Feature: a\nScenario: b\n  Given a\n    ```\n  text",
+			"test.feature:4:5: unterminated doc string"},
+	} {
+		_, err := ParseFeature("test.feature", strings.NewReader(v.src))
+		if err == nil {
+			t.Fatalf("expected error for %q", v.src)
+		}
+
+		assert.Equal(t, v.err, err.Error())
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	doc := parse(t, `Feature: a
+  Scenario: before any rule
+    Given a
+
+  @billing
+  Rule: Billing
+    Some rule
+
+    Background:
+      Given b
+
+    Scenario: Pay
+      Given a
+
+  Rule: Refunds
+    Scenario: Refund
+      Given a
+`)
+
+	f := doc.Feature
+	assert.Equal(t, 1, len(f.Scenarios))
+	assert.Equal(t, 2, len(f.Rules))
+
+	r := f.Rules[0]
+	assert.Equal(t, "Billing", r.Name)
+	assert.Equal(t, "Some rule", r.Description)
+	assert.Equal(t, "@billing", r.Tags[0].Name)
+	assert.Equal(t, "b", r.Background.Steps[0].Text)
+	assert.True(t, f.Rules[1].Background == nil)
+	assert.Equal(t, "Pay", r.Scenarios[0].Name)
+	assert.Equal(t, "Refund", f.Rules[1].Scenarios[0].Name)
+}
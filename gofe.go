@@ -1,10 +1,17 @@
 package gofe
 
 import (
+	"bytes"
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 )
 
@@ -25,12 +32,98 @@ type step struct {
 	name string
 	fn   StepFunc
 	reg  *regexp.Regexp
+
+	// full is reg anchored to match the whole step name
+	full *regexp.Regexp
+
+	// params are the parameter types of each capture group, only set for
+	// Cucumber Expressions
+	params []*parameterType
+
+	// converters of the Steps the step was added to
+	converters converters
+
+	// file and line the step was added from, seq the order it was added in
+	file string
+	line int
+	seq  uint64
 }
 
-type Steps map[string]*step
+// added counts the steps added, ordering steps added from the same line
+var added uint64
+
+// param returns the i'th submatch as a param
+func (s *step) param(i int, v string) *param {
+	p := &param{
+		v:          v,
+		converters: s.converters,
+	}
+	if i < len(s.params) {
+		p.typ = s.params[i]
+	}
+
+	return p
+}
+
+// MatchMode is how a step's regex is matched against a step name
+type MatchMode int
+
+const (
+	// MatchWhole requires a step's regex to match the entire step name, any
+	// surrounding whitespace of the name is ignored
+	MatchWhole MatchMode = iota
+
+	// MatchSubstring matches a step's regex anywhere within the step name, eg.
+	// `a step` matches "a step that deletes prod"
+	MatchSubstring
+)
+
+// Steps is a registry of StepFuncs and the parameter types their Cucumber
+// Expressions can use. Steps must be created with NewSteps, copies of Steps
+// share the same registry.
+type Steps struct {
+	steps      map[string]*step
+	params     map[string]*parameterType
+	converters converters
+
+	// match is how the steps' regex are matched, shared by copies of the Steps
+	match *MatchMode
+}
 
 func NewSteps() Steps {
-	return make(Steps)
+	return Steps{
+		steps:      make(map[string]*step),
+		params:     make(map[string]*parameterType),
+		converters: make(converters),
+
+		match: new(MatchMode),
+	}
+}
+
+// SetMatch sets how the steps' regex are matched, Cucumber Expressions always
+// match the whole step name. As the mode is shared by copies of the Steps it
+// can be set after the Steps are given to New.
+func (s Steps) SetMatch(m MatchMode) {
+	s.mustBeNew()
+
+	*s.match = m
+}
+
+// mode returns how the steps' regex are matched
+func (s Steps) mode() MatchMode {
+	if s.match == nil {
+		return MatchWhole
+	}
+
+	return *s.match
+}
+
+// mustBeNew panics if the Steps were not created with NewSteps, as its maps are
+// nil
+func (s Steps) mustBeNew() {
+	if s.steps == nil {
+		panic("Steps must be created with NewSteps")
+	}
 }
 
 var tt Testing = &testing.T{}
@@ -108,29 +201,75 @@ func checkStep(fn StepFunc) error {
 // Add adds a StepFunc by name. It always returns nil to allow steps to be added
 // without using an init() or some sort of initialization block
 func (s Steps) Add(name string, fn StepFunc) interface{} {
-	_, ok := s[name]
-	if ok {
-		panic(fmt.Sprintf("step `%s` already exists", name))
-	}
+	s.add(&step{
+		name: name,
+		fn:   fn,
+		reg:  regexp.MustCompile(name),
+		full: regexp.MustCompile("^(?:" + name + ")$"),
+	})
 
-	err := checkStep(fn)
+	return nil
+}
+
+// AddExpr adds a StepFunc by a Cucumber Expression rather than a regex. The
+// expression's {int}, {float}, {word}, {string} and {} parameters are passed
+// as arguments to the StepFunc.
+//
+//		s.AddExpr("I have {int} cucumber(s) in my belly/stomach",
+//			func(t Testing) func(int) {
+//				return func(n int) {
+//					//
+//				}
+//			})
+//
+func (s Steps) AddExpr(expr string, fn StepFunc) interface{} {
+	s.mustBeNew()
+
+	reg, params, err := compileExpr(expr, s.params)
 	if err != nil {
 		panic(err)
 	}
 
-	s[name] = &step{
-		name: name,
-		fn:   fn,
-		reg:  regexp.MustCompile(name),
-	}
+	s.add(&step{
+		name:   expr,
+		fn:     fn,
+		reg:    reg,
+		full:   reg,
+		params: params,
+	})
 
 	return nil
 }
 
-type Context map[string]interface{}
+// add adds the step, called directly from Add or AddExpr
+func (s Steps) add(st *step) {
+	s.mustBeNew()
+
+	_, st.file, st.line, _ = runtime.Caller(2)
+	st.seq = atomic.AddUint64(&added, 1)
+	st.converters = s.converters
+
+	_, ok := s.steps[st.name]
+	if ok {
+		panic(fmt.Sprintf("step `%s` already exists", st.name))
+	}
+
+	err := checkStep(st.fn)
+	if err != nil {
+		panic(err)
+	}
+
+	s.steps[st.name] = st
+}
+
+// Context is a set of values by key, ordered by when each key was first set
+type Context struct {
+	keys   []string
+	values map[string]interface{}
+}
 
-func (c Context) Get(k string) (interface{}, bool) {
-	v, ok := c[k]
+func (c *Context) Get(k string) (interface{}, bool) {
+	v, ok := c.values[k]
 	if !ok {
 		return nil, false
 	}
@@ -138,56 +277,116 @@ func (c Context) Get(k string) (interface{}, bool) {
 	return v, true
 }
 
-func (c Context) Set(k string, v interface{}) {
-	c[k] = v
+func (c *Context) Set(k string, v interface{}) {
+	if c.values == nil {
+		c.values = make(map[string]interface{})
+	}
+	if _, ok := c.values[k]; !ok {
+		c.keys = append(c.keys, k)
+	}
+
+	c.values[k] = v
+}
+
+// Keys returns the keys in the order they were first set
+func (c *Context) Keys() []string {
+	return append([]string(nil), c.keys...)
 }
 
 type Feature struct {
 	T       Testing
 	Steps   []Steps
-	Context Context
+	Context *Context
+
+	// Strict fails pending steps rather than skipping them
+	Strict bool
+
+	// StrictArgs fails steps given more or fewer arguments, captured or
+	// explicit, than their StepFunc takes. Otherwise any extra arguments are
+	// dropped and any missing are zero values.
+	StrictArgs bool
+
+	// SkipOnFailure skips any remaining steps once a step has failed, as the
+	// Runner does for each scenario. Errorf still allows a step to report more
+	// than one failure. A StepFunc taking a *testing.T rather than a Testing is
+	// only seen to fail if the T had not already failed.
+	SkipOnFailure bool
+
+	// location is the feature file and line of the step being run, set by the
+	// Runner
+	location string
+
+	// pending is set once any step is pending, failed once any step fails
+	pending *bool
+	failed  *bool
 }
 
 func New(t Testing, s ...Steps) *Feature {
 	return &Feature{
 		T:       t,
 		Steps:   s,
-		Context: make(map[string]interface{}),
+		Context: &Context{},
+
+		pending: new(bool),
+		failed:  new(bool),
 	}
 }
 
+// SetContext sets each value of c, in the order of their sorted keys
 func (f *Feature) SetContext(c map[string]interface{}) {
-	for k, v := range c {
-		f.Context[k] = v
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		f.Context.Set(k, c[k])
 	}
 }
 
 // getc looks up a context by type and then by key returning it's reflected
-// value.
+// value. Without a key the type must match a single context.
 func (f Feature) getc(t reflect.Type, key string) (reflect.Value, error) {
-	var v, null reflect.Value
+	var v reflect.Value
+	var keys []string
 
-	for k, c := range f.Context {
-		if vo := reflect.ValueOf(c); isGettable(vo, t) {
-			v = vo
+	for _, k := range f.Context.keys {
+		vo := reflect.ValueOf(f.Context.values[k])
+		if !isGettable(vo, t) {
+			continue
+		}
 
-			// if key == "" it's assumed the di value was nil and returning upon a
-			// matched type is enough
-			if k == key || key == "" {
-				return v, nil
-			}
+		if k == key {
+			return vo, nil
 		}
+
+		v = vo
+		keys = append(keys, "`"+k+"`")
 	}
 
-	// no matched type was found
-	if reflect.DeepEqual(v, null) {
-		return v, fmt.Errorf("%s: invalid context injection type", t.Name())
+	switch {
+	case len(keys) == 0:
+		return v, fmt.Errorf("%s: invalid context injection type", t)
+
+	case key != "":
+		return v, fmt.Errorf("%s: invalid context injection key", key)
+
+	// if key == "" it's assumed the di value was nil and a single matched type
+	// is enough
+	case len(keys) > 1:
+		return v, fmt.Errorf("%s: ambiguous context injection type, matched by %s",
+			t, strings.Join(keys, ", "))
 	}
 
-	return v, fmt.Errorf("%s: invalid context injection key", key)
+	return v, nil
 }
 
 func isGettable(v reflect.Value, t reflect.Type) bool {
+	if !v.IsValid() {
+		return false // a nil context
+	}
+
 	var vEqt = v.Type() == t
 
 	if t.Kind() != reflect.Interface {
@@ -203,13 +402,15 @@ func isGettable(v reflect.Value, t reflect.Type) bool {
 
 // C expands the Context objects to fn as type asserted arguments of fn. To
 // handle similar types, C employees an angular style Direct Injection array to
-// help attempt to match the order of the arguments.
+// help attempt to match the order of the arguments. Any arguments that can't
+// be resolved are all reported together.
 func (f Feature) C(di []string, fn interface{}) {
 	v := reflect.ValueOf(fn)
 	n := v.Type().NumIn()
 
 	args := make([]reflect.Value, n)
 
+	var errs []string
 	for i := 0; i < n; i++ {
 		var k string
 		if len(di) > 0 {
@@ -218,13 +419,18 @@ func (f Feature) C(di []string, fn interface{}) {
 
 		v, err := f.getc(v.Type().In(i), k)
 		if err != nil {
-			f.T.Fatalf("%s", err)
+			errs = append(errs, fmt.Sprintf("argument %d: %s", i+1, err))
 
-			return // testing package will exit, this is for tests
+			continue
 		}
 
 		args[i] = v
 	}
+	if len(errs) > 0 {
+		f.T.Fatalf("%s", strings.Join(errs, "\n"))
+
+		return // testing package will exit, this is for tests
+	}
 
 	v.Call(args)
 }
@@ -250,9 +456,14 @@ func (f *Feature) Setup(fn ...SetupFunc) func() {
 
 // stepFunc calls func(Testing) func(...)
 func (f Feature) stepFunc(s StepFunc) (reflect.Value, []reflect.Value) {
-	t := []reflect.Value{
-		reflect.ValueOf(f.T),
+	v := reflect.ValueOf(f.T)
+
+	// a StepFunc given a *testing.T can't be given the step's watch
+	if w, ok := f.T.(*watch); ok && !v.Type().AssignableTo(reflect.TypeOf(s).In(0)) {
+		v = reflect.ValueOf(w.Testing)
 	}
+
+	t := []reflect.Value{v}
 	fn := reflect.ValueOf(s).Call(t)[0]
 
 	n := fn.Type().NumIn()
@@ -269,12 +480,30 @@ type Step struct {
 	*Feature
 
 	name string
+
+	// params of each capture group
+	params []*param
 }
 
 func (s Step) Name() string {
 	return s.name
 }
 
+// Absent checks if the i'th capture group, from 0, did not match. An absent
+// group is given as nil to a pointer argument and otherwise the zero value.
+//
+//		s.Add(`^I pay (\d+)(?: with coupon "(.*)")?$`, func(t Testing) func(*Step, int, string) {
+//			return func(s *Step, n int, coupon string) {
+//				if !s.Absent(1) {
+//					...
+//				}
+//			}
+//		})
+//
+func (s Step) Absent(i int) bool {
+	return i < len(s.params) && s.params[i].absent
+}
+
 func checkParam(i interface{}, t reflect.Type) (reflect.Value, error) {
 	v := reflect.ValueOf(i)
 
@@ -282,29 +511,56 @@ func checkParam(i interface{}, t reflect.Type) (reflect.Value, error) {
 	if !ok {
 		return v, nil // just return it's not a param
 	}
+	if par.absent {
+		return reflect.Zero(t), nil
+	}
 
 	str := par.v
 
-	var err error
-	var p interface{}
-	switch t.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err = strconv.ParseInt(str, 0, 64)
+	// the parameter type transforms first, a converter for the arg type then
+	// takes its text, otherwise its value is given as is when assignable to the
+	// arg type, a number is converted within the range of the arg type, else the
+	// text is converted
+	var tv reflect.Value
+	if typ := par.typ; typ != nil && typ.transform != nil {
+		p, err := typ.transform(str)
+		if err != nil {
+			return reflect.Zero(t), err
+		}
+
+		tv = reflect.ValueOf(p)
+		if !tv.IsValid() {
+			return reflect.Zero(t), nil // a nil interface or pointer
+		}
+		if tv.Kind() == reflect.String {
+			str = tv.String()
+		}
+	}
 
-	case reflect.Float32, reflect.Float64:
-		p, err = strconv.ParseFloat(str, 64)
+	if fn, ok := par.converters[t]; ok {
+		p, err := fn(str)
+		if err != nil {
+			return reflect.Zero(t), err
+		}
 
-	case reflect.String:
-		p = str
+		v := reflect.ValueOf(p)
+		if !v.IsValid() {
+			return reflect.Zero(t), nil // a nil interface or pointer
+		}
 
-		// TODO other string -> to conversions
+		return v, nil
+	}
 
-	default:
-		// TODO handle
+	if tv.IsValid() {
+		if tv.Type().AssignableTo(t) {
+			return tv, nil
+		}
+		if v, ok, err := coerce(tv, t); ok {
+			return v, err
+		}
 	}
 
-	// last force covert to arg type
-	return reflect.ValueOf(p).Convert(t), err
+	return convert(str, t)
 }
 
 // argStep checks if first arg is *Step then injects it
@@ -317,9 +573,9 @@ func argStep(args []reflect.Value, t reflect.Type, s *Step) []reflect.Value {
 	return args
 }
 
-// argZero zero fills any remaining args that may not have been supplied
-func argZero(args []reflect.Value, t reflect.Type) []reflect.Value {
-	c := cap(args)
+// argZero zero fills any remaining args that may not have been supplied, other
+// than any variadic arg
+func argZero(args []reflect.Value, t reflect.Type, c int) []reflect.Value {
 	for i := len(args); i < c; i++ {
 		args = append(args, reflect.Zero(t.In(i)))
 	}
@@ -327,47 +583,215 @@ func argZero(args []reflect.Value, t reflect.Type) []reflect.Value {
 	return args
 }
 
-// argv builds out the []reflect.Value to be sent on Call()
+// argv builds out the []reflect.Value to be sent on Call(), returning an error
+// if any param can't be converted to its argument's type
 func argv(args []reflect.Value,
 	t reflect.Type,
 	s *Step,
-	a ...interface{}) []reflect.Value {
+	a ...interface{}) ([]reflect.Value, error) {
 
 	c := cap(args)
 	if c == 0 {
-		return nil
+		return nil, nil
 	}
 
 	args = argStep(args, t, s)
 
-	l := len(args) // offset, possibly from argStep
-	for i, v := range a {
-		if len(args) == c {
+	// a Table or DocString is always given to the last argument
+	var last []reflect.Value
+	if n := len(a); n > 0 && isStepArg(a[n-1], t.In(c-1)) {
+		last = append(last, reflect.ValueOf(a[n-1]))
+		a = a[:n-1]
+
+		c--
+	}
+
+	// any remaining args are collected by a variadic arg
+	if t.IsVariadic() {
+		c--
+	}
+
+	for _, v := range a {
+		i := len(args)
+		if i >= c && !t.IsVariadic() {
 			break // all arg index assigned
 		}
 
-		p, err := checkParam(v, t.In(i+l))
+		var typ reflect.Type
+		if i < c {
+			typ = t.In(i)
+		} else {
+			if p, ok := v.(*param); ok && p.absent {
+				continue // only matched groups are collected by a variadic arg
+			}
+
+			typ = t.In(c).Elem()
+		}
+
+		p, err := checkParam(v, typ)
 		if err != nil {
-			// TODO handle
+			return nil, fmt.Errorf("argument %d: %q as %s: %s", i+1, v.(*param).v,
+				typ, err)
 		}
 
 		args = append(args, p)
 	}
 
-	return argZero(args, t)
+	return append(argZero(args, t, c), last...), nil
 }
 
-// call relfects a StepFunc and calls it with any available arguments
-func (f *Feature) call(name string, s StepFunc, a ...interface{}) {
-	fn, args := f.stepFunc(s)
+// arity checks the number of arguments given matches the number of arguments
+// of the func t, other than any *Step, or is at least the number before any
+// variadic argument
+func arity(t reflect.Type, a []interface{}) error {
+	var want []string
+	for i := 0; i < t.NumIn(); i++ {
+		if i == 0 && t.In(i) == reflect.TypeOf(st) {
+			continue
+		}
+
+		typ := t.In(i).String()
+		if i == t.NumIn()-1 && t.IsVariadic() {
+			typ = "..." + t.In(i).Elem().String()
+		}
+
+		want = append(want, typ)
+	}
+
+	n := len(want)
+	if t.IsVariadic() {
+		n--
+		if len(a) >= n {
+			return nil
+		}
+	}
+	if len(a) == n {
+		return nil
+	}
+
+	have := make([]string, len(a))
+	for i, v := range a {
+		switch v := v.(type) {
+		case *param:
+			have[i] = v.String()
+
+		case nil:
+			have[i] = "nil"
+
+		default:
+			have[i] = reflect.TypeOf(v).String()
+		}
+	}
+
+	msg := "not enough arguments"
+	if len(a) > n {
+		msg = "too many arguments"
+	}
+
+	return fmt.Errorf("%s\n\thave (%s)\n\twant (%s)", msg,
+		strings.Join(have, ", "), strings.Join(want, ", "))
+}
+
+// isStepArg checks if v is a Table or DocString to be given as the func's last
+// argument
+func isStepArg(v interface{}, t reflect.Type) bool {
+	switch v.(type) {
+	case Table, DocString:
+		return t == reflect.TypeOf(v)
+	}
+
+	return false
+}
+
+// call relfects a StepFunc and calls it with any available arguments. A panic
+// is recovered and fails the step.
+func (f *Feature) call(name string, s *step, a ...interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.T.Fatalf("%s", f.panicked(name, s, r, a))
+		}
+	}()
+
+	fn, args := f.stepFunc(s.fn)
 
 	st := &Step{
 		Feature: f,
 
 		name: name,
 	}
+	for _, v := range a {
+		if p, ok := v.(*param); ok {
+			st.params = append(st.params, p)
+		}
+	}
+
+	a, err := s.bind(fn.Type(), a)
+	if err != nil {
+		f.T.Fatalf("`%s`: %s", name, err)
+
+		return // testing package will exit, this is for tests
+	}
+
+	if f.StrictArgs {
+		if err := arity(fn.Type(), a); err != nil {
+			f.T.Fatalf("`%s`: %s", name, err)
+
+			return
+		}
+	}
+
+	in, err := argv(args, fn.Type(), st, a...)
+	if err != nil {
+		f.T.Fatalf("`%s`: %s", name, err)
+
+		return // testing package will exit, this is for tests
+	}
+
+	// any results are ignored, other than a trailing error
+	out := fn.Call(in)
+	n := len(out)
+	if n == 0 || fn.Type().Out(n-1) != errType || out[n-1].IsNil() {
+		return
+	}
+
+	err = out[n-1].Interface().(error)
+	if err == ErrPending {
+		f.pend(name, "")
+
+		return
+	}
+
+	f.T.Fatalf("`%s`: %s", name, err)
+}
+
+// panicked reports a recovered panic with the step's pattern, arguments and
+// feature file location along with the stack
+func (f Feature) panicked(name string, s *step, r interface{}, a []interface{}) string {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "`%s`: panic: %v", name, r)
+	if s.name != "" {
+		fmt.Fprintf(&b, "\n\tstep: `%s` (%s:%d)", s.name, filepath.Base(s.file), s.line)
+	}
+	if len(a) > 0 {
+		args := make([]string, len(a))
+		for i, v := range a {
+			if p, ok := v.(*param); ok {
+				args[i] = p.String()
+			} else {
+				args[i] = fmt.Sprintf("%#v", v)
+			}
+		}
+
+		fmt.Fprintf(&b, "\n\targs: %s", strings.Join(args, ", "))
+	}
+	if f.location != "" {
+		fmt.Fprintf(&b, "\n\tat: %s", f.location)
+	}
+
+	fmt.Fprintf(&b, "\n\n%s", debug.Stack())
 
-	fn.Call(argv(args, fn.Type(), st, a...))
+	return b.String()
 }
 
 // Stepf calls a given StepFunc directly
@@ -378,42 +802,154 @@ func (f Feature) Stepf(fn StepFunc, a ...interface{}) {
 
 		return
 	}
+	if f.skipped("") {
+		return
+	}
 
-	f.call("", fn, a...)
+	w := watching(f.T)
+	f.T = w
+	defer f.track(w)
+
+	f.call("", &step{fn: fn}, a...)
 }
 
 type param struct {
-	v string
+	v   string
+	typ *parameterType
+
+	converters converters
+
+	// absent is set if the param's capture group did not match
+	absent bool
 }
 
-// Step looks up a step by name and calls it
-func (f Feature) Step(name string, a ...interface{}) {
-	var fn StepFunc
-	var args []interface{}
+func (p *param) String() string {
+	if p.absent {
+		return "nil"
+	}
 
+	return strconv.Quote(p.v)
+}
+
+// match is a step matching a step name and its submatches as args
+type match struct {
+	*step
+
+	args []interface{}
+}
+
+type byLocation []*match
+
+func (m byLocation) Len() int      { return len(m) }
+func (m byLocation) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m byLocation) Less(i, j int) bool {
+	if m[i].file != m[j].file {
+		return m[i].file < m[j].file
+	}
+	if m[i].line != m[j].line {
+		return m[i].line < m[j].line
+	}
+
+	return m[i].seq < m[j].seq
+}
+
+// match returns every step matching name, ordered by where the steps were
+// added
+func (f Feature) match(name string) []*match {
+	var ms []*match
+
+	seen := make(map[*step]bool)
 	for _, s := range f.Steps {
-		for _, v := range s {
-			m := v.reg.FindStringSubmatch(name)
-			if n := len(m); n > 0 {
-				fn = v.fn
-
-				// start at 1, we only want the submatches
-				for i := 1; i < n; i++ {
-					args = append(args, &param{m[i]})
+		text := name
+		if s.mode() == MatchWhole {
+			text = strings.TrimSpace(name)
+		}
+
+		for _, v := range s.steps {
+			reg := v.full
+			if s.mode() == MatchSubstring {
+				reg = v.reg
+			}
+
+			m := reg.FindStringSubmatchIndex(text)
+			if len(m) == 0 || seen[v] {
+				continue
+			}
+			seen[v] = true
+
+			var args []interface{}
+
+			// start at 1, we only want the submatches
+			for i := 1; i < len(m)/2; i++ {
+				j, k := m[2*i], m[2*i+1]
+				if j < 0 {
+					p := v.param(i-1, "")
+					p.absent = true
+
+					args = append(args, p)
+
+					continue
 				}
 
-				break
+				args = append(args, v.param(i-1, text[j:k]))
 			}
+
+			ms = append(ms, &match{
+				step: v,
+				args: args,
+			})
 		}
 	}
 
-	if fn == nil {
-		f.T.Fatalf("`%s`: step not found", name)
+	sort.Sort(byLocation(ms))
+
+	return ms
+}
+
+// ambiguous lists each of the matched steps
+func ambiguous(name string, ms []*match) string {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "`%s`: ambiguous step, matched by:", name)
+	for _, v := range ms {
+		writeSteps(&b, v.step)
+	}
+
+	return b.String()
+}
+
+// Step looks up a step by name and calls it
+func (f Feature) Step(name string, a ...interface{}) {
+	if f.skipped(name) {
+		return
+	}
+
+	w := watching(f.T)
+	f.T = w
+	defer f.track(w)
+
+	ms := f.match(name)
+
+	switch len(ms) {
+	case 0:
+		_, sn := snippet(name, a...)
+
+		f.T.Logf("`%s` is undefined, implement it with:\n\n%s", name, sn)
+		f.T.Fatalf("%s", f.notFound(name))
 
 		return // actual testing package will exit, just for testing
+
+	case 1:
+		// ok
+
+	default:
+		f.T.Fatalf("%s", ambiguous(name, ms))
+
+		return
 	}
 
-	f.call(name, fn, append(args, a...)...)
+	m := ms[0]
+	f.call(name, m.step, append(m.args, a...)...)
 }
 
 /*
@@ -2,6 +2,8 @@ package gofe
 
 import (
 	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 
 	"gopkg.in/nowk/assert.v2"
@@ -14,6 +16,7 @@ type tTesting struct {
 	fatals  []string
 	fatalfs []string
 	logfs   []string
+	skipfs  []string
 }
 
 func (t *tTesting) Errorf(f string, v ...interface{}) {
@@ -32,6 +35,16 @@ func (t *tTesting) Logf(f string, v ...interface{}) {
 	t.logfs = append(t.logfs, fmt.Sprintf(f, v...))
 }
 
+func (t *tTesting) Skipf(f string, v ...interface{}) {
+	t.skipfs = append(t.skipfs, fmt.Sprintf(f, v...))
+}
+
+func (t *tTesting) Failed() bool {
+	return len(t.errorfs)+len(t.fatals)+len(t.fatalfs) > 0
+}
+
+func (t *tTesting) Helper() {}
+
 func TestStepsBasicTypes(t *testing.T) {
 	tT := new(tTesting)
 
@@ -344,8 +357,8 @@ func TestCArgTypesMustMatchMatchedContextType(t *testing.T) {
 	fe.Step("a step")
 	fe.Step("another step")
 
-	assert.Equal(t, "User: invalid context injection type", tT.fatalfs[0])
-	assert.Equal(t, "User: invalid context injection type", tT.fatalfs[1])
+	assert.Equal(t, "argument 1: gofe.User: invalid context injection type", tT.fatalfs[0])
+	assert.Equal(t, "argument 1: gofe.User: invalid context injection type", tT.fatalfs[1])
 }
 
 func TestCArgDiMustHaveAMatchingKey(t *testing.T) {
@@ -370,7 +383,7 @@ func TestCArgDiMustHaveAMatchingKey(t *testing.T) {
 	})
 	fe.Step("a step")
 
-	assert.Equal(t, "User: invalid context injection key", tT.fatalfs[0])
+	assert.Equal(t, "argument 1: User: invalid context injection key", tT.fatalfs[0])
 }
 
 func TestCExpandsOnTypeIfDiIsNil(t *testing.T) {
@@ -583,3 +596,494 @@ func TestOmittingArgsFromCallByNotDefningArgInStepFunc(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("edit label #%d", i+1), tT.logfs[i+4])
 	}
 }
+
+func TestTableIsGivenToTheLastArgument(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add(`^(\d+) users exist:$`, func(t Testing) func(*Step, int, Table) {
+		return func(_ *Step, n int, tbl Table) {
+			t.Logf("%d users, %d rows", n, len(tbl.Rows()))
+		}
+	})
+	s.Add(`^users exist:$`, func(t Testing) func(Table) {
+		return func(tbl Table) {
+			t.Logf("%d rows", len(tbl.Rows()))
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("2 users exist:", users)
+	fe.Step("users exist:", users)
+	fe.Step("2 users exist:")
+
+	assert.Equal(t, "2 users, 2 rows", tT.logfs[0])
+	assert.Equal(t, "2 rows", tT.logfs[1])
+	assert.Equal(t, "2 users, 0 rows", tT.logfs[2])
+}
+
+func TestDocStringIsGivenToTheLastArgument(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add(`^I (\w+) the body:$`, func(t Testing) func(string, DocString) {
+		return func(method string, d DocString) {
+			t.Logf("%s %s: %s", method, d.MediaType, d)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("I POST the body:", DocString{
+		Content:   `{"name": "Batman"}`,
+		MediaType: "json",
+	})
+
+	assert.Equal(t, `POST json: {"name": "Batman"}`, tT.logfs[0])
+}
+
+func TestAmbiguousStepsFailListingEachMatch(t *testing.T) {
+	tT := &tTesting{}
+
+	fn := func(t Testing) func() {
+		return func() {
+			t.Logf("called")
+		}
+	}
+
+	_, _, line, _ := runtime.Caller(0)
+
+	a, b := NewSteps(), NewSteps()
+	a.Add(`^I have (\d+) cukes$`, fn)
+	b.AddExpr("I have {int} cuke(s)", fn)
+	a.Add(".* cukes", fn)
+
+	fe := New(tT, a, b)
+	fe.Step("I have 5 cukes")
+
+	assert.Equal(t, fmt.Sprintf("`I have 5 cukes`: ambiguous step, matched by:"+
+		"\n\t`^I have (\\d+) cukes$` (gofe_test.go:%d)"+
+		"\n\t`I have {int} cuke(s)` (gofe_test.go:%d)"+
+		"\n\t`.* cukes` (gofe_test.go:%d)", line+3, line+4, line+5), tT.fatalfs[0])
+	assert.Equal(t, 0, len(tT.logfs))
+}
+
+func TestAmbiguousStepsAddedFromTheSameLineAreListedInOrder(t *testing.T) {
+	fn := func(t Testing) func() {
+		return func() {}
+	}
+
+	_, _, line, _ := runtime.Caller(0)
+
+	s := NewSteps()
+	for _, v := range []string{"a (.*)", "(.*) step", "a st(.*)", "(.*)tep"} {
+		s.Add(v, fn)
+	}
+
+	// the steps are ranged over from a map, so check the order is stable
+	for i := 0; i < 20; i++ {
+		tT := &tTesting{}
+
+		fe := New(tT, s)
+		fe.Step("a step")
+
+		assert.Equal(t, []string{fmt.Sprintf("`a step`: ambiguous step, matched by:"+
+			"\n\t`a (.*)` (gofe_test.go:%[1]d)"+
+			"\n\t`(.*) step` (gofe_test.go:%[1]d)"+
+			"\n\t`a st(.*)` (gofe_test.go:%[1]d)"+
+			"\n\t`(.*)tep` (gofe_test.go:%[1]d)", line+4)}, tT.fatalfs)
+	}
+}
+
+func TestTheSameStepsGivenTwiceIsNotAmbiguous(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func() {
+		return func() {
+			t.Logf("called")
+		}
+	})
+
+	fe := New(tT, s, s)
+	fe.Step("a step")
+
+	assert.Equal(t, "called", tT.logfs[0])
+}
+
+func TestStepsMatchTheWholeStepName(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []string
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func() {
+		return func() {
+			ran = append(ran, "a step")
+		}
+	})
+	s.Add(`(\d+) cukes`, func(t Testing) func(int) {
+		return func(n int) {
+			ran = append(ran, fmt.Sprintf("%d cukes", n))
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a step that deletes prod")
+	fe.Step("  a step\t")
+	fe.Step("12 cukes")
+	fe.Step("I have 12 cukes")
+
+	assert.Equal(t, []string{"a step", "12 cukes"}, ran)
+	assert.Equal(t, []string{
+		"`a step that deletes prod`: step not found",
+		"`I have 12 cukes`: step not found",
+	}, tT.fatalfs)
+}
+
+func TestStepsCanMatchSubstringsOfTheStepName(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []string
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func() {
+		return func() {
+			ran = append(ran, "a step")
+		}
+	})
+	s.Add(`(\d+) cukes`, func(t Testing) func(int) {
+		return func(n int) {
+			ran = append(ran, fmt.Sprintf("%d cukes", n))
+		}
+	})
+	s.AddExpr("{int} cucumbers", func(t Testing) func(int) {
+		return func(n int) {
+			ran = append(ran, fmt.Sprintf("%d cucumbers", n))
+		}
+	})
+
+	// the mode is shared with the Feature's copy of the Steps
+	fe := New(tT, s)
+	s.SetMatch(MatchSubstring)
+	fe.Step("a step that deletes prod")
+	fe.Step("I have 12 cukes")
+	fe.Step("I have 12 cucumbers")
+
+	assert.Equal(t, []string{"a step", "12 cukes"}, ran)
+	assert.True(t, strings.HasPrefix(tT.fatalfs[0],
+		"`I have 12 cucumbers`: step not found"))
+}
+
+func TestStepsMustBeCreatedWithNewSteps(t *testing.T) {
+	var s Steps
+
+	fn := func(t Testing) func() {
+		return func() {}
+	}
+
+	for _, v := range []func(){
+		func() { s.Add("a step", fn) },
+		func() { s.AddExpr("a step", fn) },
+		func() { s.ParameterType("colour", "red|blue", nil) },
+		func() { s.Convert(parseMoney) },
+		func() { s.SetMatch(MatchSubstring) },
+	} {
+		assert.Panic(t, "Steps must be created with NewSteps", v)
+	}
+}
+
+func TestStepNotFoundLogsASnippet(t *testing.T) {
+	tT := &tTesting{}
+
+	fe := New(tT, NewSteps())
+	fe.Step("I have 42 cukes")
+
+	assert.Equal(t, "`I have 42 cukes` is undefined, implement it with:\n\n"+
+		`s.Add("^I have (\\d+) cukes$", func(t gofe.Testing) func(int) {
+	return func(arg1 int) {
+		// TODO implement
+	}
+})`, tT.logfs[0])
+	assert.Equal(t, "`I have 42 cukes`: step not found", tT.fatalfs[0])
+}
+
+func TestStrictArgsFailsOnTheWrongNumberOfArguments(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []int
+
+	s := NewSteps()
+	s.Add(`^(\d+) \+ (\d+) = (\d+)$`, func(t Testing) func(*Step, int, int, int) {
+		return func(s *Step, a, b, c int) {
+			ran = append(ran, a, b, c)
+		}
+	})
+	s.Add("^a \\+ b = c$", func(t Testing) func(int, int, int) {
+		return func(a, b, c int) {
+			ran = append(ran, a, b, c)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.StrictArgs = true
+	fe.Step("1 + 2 = 3")
+	fe.Step("a + b = c", 1, 2)
+	fe.Step("a + b = c", 1, 2, 3, nil, Table{})
+	fe.Step("1 + 2 = 3", 4)
+
+	assert.Equal(t, []int{1, 2, 3}, ran)
+	assert.Equal(t, []string{
+		"`a + b = c`: not enough arguments\n" +
+			"\thave (int, int)\n" +
+			"\twant (int, int, int)",
+		"`a + b = c`: too many arguments\n" +
+			"\thave (int, int, int, nil, gofe.Table)\n" +
+			"\twant (int, int, int)",
+		"`1 + 2 = 3`: too many arguments\n" +
+			"\thave (\"1\", \"2\", \"3\", int)\n" +
+			"\twant (int, int, int)",
+	}, tT.fatalfs)
+}
+
+func TestArgsAreDroppedOrZeroFilledByDefault(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []int
+
+	s := NewSteps()
+	s.Add("^a \\+ b = c$", func(t Testing) func(int, int, int) {
+		return func(a, b, c int) {
+			ran = append(ran, a, b, c)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a + b = c", 1, 2)
+	fe.Step("a + b = c", 1, 2, 3, 4)
+
+	assert.Equal(t, []int{1, 2, 0, 1, 2, 3}, ran)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestVariadicStepFuncsCollectTheRemainingArgs(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Add(`^the cart contains (\w+), (\w+) and (\w+)$`,
+		func(t Testing) func(...string) {
+			return func(names ...string) {
+				got = append(got, names)
+			}
+		})
+	s.Add(`^add (\d+) to$`, func(t Testing) func(*Step, int, ...int) {
+		return func(s *Step, a int, b ...int) {
+			got = append(got, a, b)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.StrictArgs = true
+	fe.Step("the cart contains apples, pears and plums")
+	fe.Step("add 5 to", 1, 2)
+	fe.Step("add 5 to")
+
+	assert.Equal(t, []interface{}{
+		[]string{"apples", "pears", "plums"},
+		5, []int{1, 2},
+		5, []int{},
+	}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestVariadicStepFuncConversionAndArityErrors(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add(`^add (\w+) to (\w+)$`, func(t Testing) func(int, ...int) {
+		return func(int, ...int) {}
+	})
+	s.Add(`^add$`, func(t Testing) func(int, ...int) {
+		return func(int, ...int) {}
+	})
+
+	fe := New(tT, s)
+	fe.StrictArgs = true
+	fe.Step("add 1 to x")
+	fe.Step("add")
+
+	assert.Equal(t, []string{
+		"`add 1 to x`: argument 2: \"x\" as int: " +
+			"strconv.ParseInt: parsing \"x\": invalid syntax",
+		"`add`: not enough arguments\n" +
+			"\thave ()\n" +
+			"\twant (int, ...int)",
+	}, tT.fatalfs)
+}
+
+func TestOptionalCaptureGroups(t *testing.T) {
+	tT := &tTesting{}
+
+	var got []interface{}
+
+	s := NewSteps()
+	s.Add(`^I pay (\d+)(?: with coupon "(.*)")?(?: in (\d+) parts)?$`,
+		func(t Testing) func(*Step, int, *string, int) {
+			return func(s *Step, n int, coupon *string, parts int) {
+				got = append(got, n, coupon, parts, s.Absent(1), s.Absent(2))
+			}
+		})
+
+	fe := New(tT, s)
+	fe.Step(`I pay 5 with coupon "half"`)
+	fe.Step(`I pay 5 with coupon ""`)
+	fe.Step(`I pay 5 in 3 parts`)
+
+	half, empty := "half", ""
+
+	assert.Equal(t, []interface{}{
+		5, &half, 0, false, true,
+		5, &empty, 0, false, true,
+		5, (*string)(nil), 3, true, false,
+	}, got)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestOptionalCaptureGroupsAreNotCollectedByVariadicArgs(t *testing.T) {
+	tT := &tTesting{}
+
+	var got [][]string
+
+	s := NewSteps()
+	s.Add(`^the cart contains (\w+)(?:, (\w+))?(?:, (\w+))?(?: and (\w+))?$`,
+		func(t Testing) func(...string) {
+			return func(names ...string) {
+				got = append(got, names)
+			}
+		})
+
+	fe := New(tT, s)
+	fe.Step("the cart contains apples")
+	fe.Step("the cart contains apples and plums")
+	fe.Step("the cart contains apples, pears, kiwis and plums")
+
+	assert.Equal(t, [][]string{
+		{"apples"},
+		{"apples", "plums"},
+		{"apples", "pears", "kiwis", "plums"},
+	}, got)
+}
+
+func TestContextIsOrderedByInsertion(t *testing.T) {
+	c := &Context{}
+	c.Set("b", 1)
+	c.Set("a", 2)
+	c.Set("b", 3)
+
+	v, ok := c.Get("b")
+
+	assert.Equal(t, []string{"b", "a"}, c.Keys())
+	assert.Equal(t, 3, v)
+	assert.True(t, ok)
+
+	fe := New(&tTesting{})
+	fe.SetContext(map[string]interface{}{
+		"c": 1,
+		"a": 2,
+		"b": 3,
+	})
+
+	assert.Equal(t, []string{"a", "b", "c"}, fe.Context.Keys())
+}
+
+func TestCFailsOnAmbiguousContextTypes(t *testing.T) {
+	tT := &tTesting{}
+
+	type User struct {
+		Name string
+	}
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			s.C(nil, func(u *User) {
+				t.Logf("u: %s", u.Name)
+			})
+		}
+	})
+	s.Add("another step", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			s.C([]string{"robin"}, func(u *User) {
+				t.Logf("u: %s", u.Name)
+			})
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Context.Set("robin", &User{"Robin"})
+	fe.Context.Set("batman", &User{"Batman"})
+	fe.Step("a step")
+	fe.Step("another step")
+
+	assert.Equal(t, []string{
+		"argument 1: *gofe.User: ambiguous context injection type, " +
+			"matched by `robin`, `batman`",
+	}, tT.fatalfs)
+	assert.Equal(t, []string{"u: Robin"}, tT.logfs)
+}
+
+func TestCReportsAllUnresolvedArgs(t *testing.T) {
+	tT := &tTesting{}
+
+	type User struct {
+		Name string
+	}
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			s.C([]string{"a", "n", "u"}, func(a string, n int, u User) {
+				t.Logf("called")
+			})
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Context.Set("a", "a")
+	fe.Context.Set("b", 1)
+	fe.Context.Set("nil", nil)
+	fe.Step("a step")
+
+	assert.Equal(t, []string{
+		"argument 2: n: invalid context injection key\n" +
+			"argument 3: gofe.User: invalid context injection type",
+	}, tT.fatalfs)
+	assert.Equal(t, 0, len(tT.logfs))
+}
+
+func TestCReportsUnresolvedArgsByType(t *testing.T) {
+	tT := &tTesting{}
+
+	type A struct{}
+	type B struct{}
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			s.C(nil, func(*A, *B, []string) {
+				t.Logf("called")
+			})
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a step")
+
+	assert.Equal(t, []string{
+		"argument 1: *gofe.A: invalid context injection type\n" +
+			"argument 2: *gofe.B: invalid context injection type\n" +
+			"argument 3: []string: invalid context injection type",
+	}, tT.fatalfs)
+	assert.Equal(t, 0, len(tT.logfs))
+}
@@ -0,0 +1,71 @@
+//go:build go1.18
+// +build go1.18
+
+package gofe
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Key is a Context key for values of type T, so a value of the wrong type is a
+// compile error rather than a failed type assertion.
+//
+//		var UserKey = gofe.NewKey[*User]("user")
+//
+//		s.Add("^I am signed in$", func(t Testing) func(*Step) {
+//			return func(s *Step) {
+//				gofe.Set(s.Context, UserKey, &User{"Batman"})
+//			}
+//		})
+//		s.Add("^I see my name$", func(t Testing) func(*Step) {
+//			return func(s *Step) {
+//				u := gofe.MustGet(s.Context, UserKey)
+//				...
+//			}
+//		})
+//
+type Key[T any] struct {
+	name string
+}
+
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{
+		name: name,
+	}
+}
+
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Set sets the value of the key
+func Set[T any](c *Context, k Key[T], v T) {
+	c.Set(k.name, v)
+}
+
+// Get returns the value of the key, false if it is not set or was set to a
+// value of another type through Context.Set
+func Get[T any](c *Context, k Key[T]) (T, bool) {
+	v, _ := c.Get(k.name)
+	t, ok := v.(T)
+
+	return t, ok
+}
+
+// MustGet returns the value of the key, panicking with the key's name if it is
+// not set or is of another type. Within a step the panic fails the step.
+func MustGet[T any](c *Context, k Key[T]) T {
+	v, ok := c.Get(k.name)
+	if !ok {
+		panic(fmt.Sprintf("context `%s` is not set", k.name))
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		panic(fmt.Sprintf("context `%s` is %T, not %s", k.name, v,
+			reflect.TypeOf((*T)(nil)).Elem()))
+	}
+
+	return t
+}
@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package gofe
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+type keyUser struct {
+	Name string
+}
+
+var userKey = NewKey[*keyUser]("user")
+
+func TestTypedContextKeys(t *testing.T) {
+	c := &Context{}
+
+	u, ok := Get(c, userKey)
+	assert.True(t, u == nil)
+	assert.Equal(t, false, ok)
+
+	Set(c, userKey, &keyUser{"Batman"})
+
+	u, ok = Get(c, userKey)
+	assert.Equal(t, &keyUser{"Batman"}, u)
+	assert.True(t, ok)
+	assert.Equal(t, &keyUser{"Batman"}, MustGet(c, userKey))
+	assert.Equal(t, "user", userKey.Name())
+
+	c.Set("user", "Robin")
+
+	u, ok = Get(c, userKey)
+	assert.True(t, u == nil)
+	assert.Equal(t, false, ok)
+}
+
+func TestMustGetPanicsWithTheKeyName(t *testing.T) {
+	c := &Context{}
+
+	assert.Panic(t, "context `user` is not set", func() {
+		MustGet(c, userKey)
+	})
+
+	c.Set("user", "Robin")
+
+	assert.Panic(t, "context `user` is string, not *gofe.keyUser", func() {
+		MustGet(c, userKey)
+	})
+
+	var stringer = NewKey[fmt.Stringer]("stringer")
+	c.Set("stringer", 1)
+
+	assert.Panic(t, "context `stringer` is int, not fmt.Stringer", func() {
+		MustGet(c, stringer)
+	})
+}
+
+func TestMustGetFailsTheStep(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add("I see my name", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			t.Logf("name: %s", MustGet(s.Context, userKey).Name)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("I see my name")
+	Set(fe.Context, userKey, &keyUser{"Batman"})
+	fe.Step("I see my name")
+
+	assert.Equal(t, 1, len(tT.fatalfs))
+	assert.True(t, strings.HasPrefix(tT.fatalfs[0],
+		"`I see my name`: panic: context `user` is not set"))
+	assert.Equal(t, []string{"name: Batman"}, tT.logfs)
+}
@@ -0,0 +1,405 @@
+package gofe
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runner runs Gherkin .feature files as go test subtests. Each Feature is run
+// as a subtest of t and each of its Scenarios as a subtest of the Feature, so
+// a single scenario can be selected with
+//
+//		go test -run 'TestFeatures/Checkout/Guest_pays'
+//
+type Runner struct {
+	Steps []Steps
+
+	// Tags is a tag expression selecting which scenarios are run, see
+	// ParseTagExpr. If empty the -gofe.tags flag or GOFE_TAGS environment
+	// variable is used.
+	Tags string
+
+	// Strict fails scenarios with pending steps rather than skipping them
+	Strict bool
+
+	// StrictArgs fails steps given more or fewer arguments than their StepFunc
+	// takes, see Feature.StrictArgs. NewRunner enables it.
+	StrictArgs bool
+
+	// run runs fn as a named subtest of t
+	run func(t Testing, name string, fn func(Testing)) bool
+}
+
+var tagsFlag = flag.String("gofe.tags", "",
+	"run only scenarios matching the tag expression, defaults to $GOFE_TAGS")
+
+func NewRunner(s ...Steps) *Runner {
+	return &Runner{
+		Steps:      s,
+		StrictArgs: true,
+	}
+}
+
+// RunFeatures runs all feature files matching pattern, see filepath.Match for
+// the pattern syntax.
+//
+//		func TestFeatures(t *testing.T) {
+//			gofe.RunFeatures(t, "features/*.feature", steps)
+//		}
+//
+func RunFeatures(t *testing.T, pattern string, s ...Steps) {
+	NewRunner(s...).Run(t, pattern)
+}
+
+// subtest runs fn through testing.T's Run
+func subtest(t Testing, name string, fn func(Testing)) bool {
+	return t.(*testing.T).Run(name, func(t *testing.T) {
+		fn(t)
+	})
+}
+
+// Run runs all feature files matching pattern as subtests of t
+func (r *Runner) Run(t *testing.T, pattern string) {
+	r.runFiles(t, pattern)
+}
+
+func (r *Runner) subtest(t Testing, name string, fn func(Testing)) bool {
+	if r.run != nil {
+		return r.run(t, name, fn)
+	}
+
+	return subtest(t, name, fn)
+}
+
+// tagExpr returns the parsed tag expression from Tags, the -gofe.tags flag or
+// the GOFE_TAGS environment variable, in that order
+func (r *Runner) tagExpr() (TagExpr, error) {
+	s := r.Tags
+	if s == "" {
+		s = *tagsFlag
+	}
+	if s == "" {
+		s = os.Getenv("GOFE_TAGS")
+	}
+
+	return ParseTagExpr(s)
+}
+
+func (r *Runner) runFiles(t Testing, pattern string) {
+	x, err := r.tagExpr()
+	if err != nil {
+		t.Fatalf("%s", err)
+
+		return // testing package will exit, this is for tests
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatalf("%s: %s", pattern, err)
+
+		return
+	}
+	if len(paths) == 0 {
+		t.Fatalf("%s: no feature files found", pattern)
+
+		return
+	}
+
+	s := &suite{
+		Runner: r,
+		tags:   x,
+		seen:   make(map[string]bool),
+	}
+
+	for _, v := range paths {
+		doc, err := ParseFeatureFile(v)
+		if err != nil {
+			t.Errorf("%s", err)
+
+			continue
+		}
+
+		s.runDocument(t, doc)
+	}
+
+	if len(s.snippets) > 0 {
+		t.Logf("undefined steps, implement them with:\n\n%s",
+			strings.Join(s.snippets, "\n\n"))
+	}
+}
+
+// suite is a single run of a Runner
+type suite struct {
+	*Runner
+
+	tags TagExpr
+
+	// snippets for each undefined step, seen by their regex
+	snippets []string
+	seen     map[string]bool
+}
+
+// tagNames returns the names of the tags, appended to any inherited names
+func tagNames(names []string, tags []*Tag) []string {
+	n := make([]string, len(names), len(names)+len(tags))
+	copy(n, names)
+
+	for _, v := range tags {
+		n = append(n, v.Name)
+	}
+
+	return n
+}
+
+// selected checks if any of the scenarios, or any row of a Scenario Outline,
+// match the tag expression
+func selected(x TagExpr, tags []string, scs ...*ScenarioNode) bool {
+	for _, sc := range scs {
+		tags := tagNames(tags, sc.Tags)
+		if !sc.Outline() {
+			if x.Match(tags) {
+				return true
+			}
+
+			continue
+		}
+
+		for _, ex := range sc.Examples {
+			if len(ex.Rows) > 1 && x.Match(tagNames(tags, ex.Tags)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// runDocument runs a parsed Document's Feature as a subtest of t, only those
+// scenarios matching the tag expression are run
+func (s *suite) runDocument(t Testing, doc *Document) {
+	f := doc.Feature
+	if f == nil {
+		return
+	}
+
+	tags := tagNames(nil, f.Tags)
+
+	ok := selected(s.tags, tags, f.Scenarios...)
+	for _, v := range f.Rules {
+		ok = ok || selected(s.tags, tagNames(tags, v.Tags), v.Scenarios...)
+	}
+	if !ok {
+		return
+	}
+
+	name := f.Name
+	if name == "" {
+		name = filepath.Base(doc.Path)
+	}
+
+	bg := background(nil, f.Background)
+
+	s.subtest(t, name, func(t Testing) {
+		s.runScenarios(t, doc, tags, bg, f.Scenarios)
+
+		for _, v := range f.Rules {
+			rule := v
+
+			tags := tagNames(tags, rule.Tags)
+			if !selected(s.tags, tags, rule.Scenarios...) {
+				continue
+			}
+
+			bg := background(bg, rule.Background)
+
+			s.subtest(t, rule.Name, func(t Testing) {
+				s.runScenarios(t, doc, tags, bg, rule.Scenarios)
+			})
+		}
+	})
+}
+
+// background returns the Background's steps, appended to any inherited
+// Background steps
+func background(steps []*StepNode, b *BackgroundNode) []*StepNode {
+	if b == nil {
+		return steps
+	}
+
+	return append(steps[:len(steps):len(steps)], b.Steps...)
+}
+
+func (s *suite) runScenarios(t Testing,
+	doc *Document,
+	tags []string,
+	bg []*StepNode,
+	scs []*ScenarioNode) {
+
+	for _, v := range scs {
+		sc := v
+		if !selected(s.tags, tags, sc) {
+			continue
+		}
+
+		s.subtest(t, sc.Name, func(t Testing) {
+			if sc.Outline() {
+				s.runOutline(t, doc, tagNames(tags, sc.Tags), bg, sc)
+
+				return
+			}
+
+			s.runScenario(t, doc, bg, sc.Steps)
+		})
+	}
+}
+
+// runOutline runs each row of the Scenario Outline's Examples as a subtest
+// named by the row's values
+func (s *suite) runOutline(t Testing,
+	doc *Document,
+	tags []string,
+	bg []*StepNode,
+	sc *ScenarioNode) {
+
+	for _, ex := range sc.Examples {
+		if len(ex.Rows) == 0 || !s.tags.Match(tagNames(tags, ex.Tags)) {
+			continue
+		}
+
+		header := ex.Rows[0].Values()
+		for _, row := range ex.Rows[1:] {
+			values := row.Values()
+			steps := expandSteps(sc.Steps, header, values)
+
+			s.subtest(t, strings.Join(values, ","), func(t Testing) {
+				s.runScenario(t, doc, bg, steps)
+			})
+		}
+	}
+}
+
+// expandSteps returns a copy of the steps with each <placeholder> replaced by
+// the value of the matching header
+func expandSteps(steps []*StepNode, header, values []string) []*StepNode {
+	var kv []string
+	for i, k := range header {
+		kv = append(kv, "<"+k+">", values[i])
+	}
+	rep := strings.NewReplacer(kv...)
+
+	ex := make([]*StepNode, len(steps))
+	for i, v := range steps {
+		s := *v
+		s.Text = rep.Replace(v.Text)
+		s.DataTable = nil
+
+		if d := v.DocString; d != nil {
+			ds := *d
+			ds.Content = rep.Replace(d.Content)
+
+			s.DocString = &ds
+		}
+
+		for _, row := range v.DataTable {
+			r := *row
+			r.Cells = make([]*TableCell, len(row.Cells))
+			for j, c := range row.Cells {
+				r.Cells[j] = &TableCell{
+					Location: c.Location,
+					Value:    rep.Replace(c.Value),
+				}
+			}
+
+			s.DataTable = append(s.DataTable, &r)
+		}
+
+		ex[i] = &s
+	}
+
+	return ex
+}
+
+// runScenario runs the Background steps followed by the scenario's steps
+// against a new Feature, so each scenario starts with a fresh Context
+func (s *suite) runScenario(t Testing,
+	doc *Document,
+	bg []*StepNode,
+	sc []*StepNode) {
+
+	steps := append(bg[:len(bg):len(bg)], sc...)
+
+	fe := New(t, s.Steps...)
+	fe.Strict = s.Strict
+	fe.StrictArgs = s.StrictArgs
+	fe.SkipOnFailure = true
+	if !s.defined(t, doc, fe, steps) {
+		return
+	}
+
+	// a pending step's Skipf or a step's Fatalf ends the test before all steps
+	// are run
+	i := 0
+	defer func() {
+		for _, v := range steps[i:] {
+			t.Logf("%s:%d: %s %s (skipped)", doc.Path, v.Location.Line, v.Keyword,
+				v.Text)
+		}
+	}()
+
+	for i < len(steps) && !fe.halted() {
+		v := steps[i]
+		i++
+
+		t.Logf("%s:%d: %s %s", doc.Path, v.Location.Line, v.Keyword, v.Text)
+
+		fe.location = fmt.Sprintf("%s:%d", doc.Path, v.Location.Line)
+		fe.Step(v.Text, stepArgs(v)...)
+	}
+}
+
+// defined checks all of the steps are defined before any are run, failing on
+// and collecting a snippet for each undefined step
+func (s *suite) defined(t Testing,
+	doc *Document,
+	fe *Feature,
+	steps []*StepNode) bool {
+
+	ok := true
+	for _, v := range steps {
+		if len(fe.match(v.Text)) > 0 {
+			continue
+		}
+
+		t.Errorf("%s:%d: %s", doc.Path, v.Location.Line, fe.notFound(v.Text))
+		ok = false
+
+		reg, sn := snippet(v.Text, stepArgs(v)...)
+		if !s.seen[reg] {
+			s.seen[reg] = true
+			s.snippets = append(s.snippets, sn)
+		}
+	}
+
+	return ok
+}
+
+// stepArgs returns the step's data table or doc string as a Table or DocString
+// argument
+func stepArgs(s *StepNode) []interface{} {
+	if len(s.DataTable) > 0 {
+		return []interface{}{tableOf(s.DataTable)}
+	}
+
+	if d := s.DocString; d != nil {
+		return []interface{}{DocString{
+			Content:   d.Content,
+			MediaType: d.MediaType,
+		}}
+	}
+
+	return nil
+}
@@ -0,0 +1,522 @@
+package gofe
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+// TestMain clears any -gofe.tags flag or GOFE_TAGS environment variable the
+// tests are run with, so every scenario of the test features is run
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	*tagsFlag = ""
+	os.Unsetenv("GOFE_TAGS")
+
+	os.Exit(m.Run())
+}
+
+// fakeRun runs subtests inline against the parent Testing, recording the path
+// of each subtest
+func fakeRun(names *[]string) func(Testing, string, func(Testing)) bool {
+	return func(t Testing, name string, fn func(Testing)) bool {
+		*names = append(*names, name)
+		fn(t)
+
+		return true
+	}
+}
+
+func calculatorSteps(log *[]string) Steps {
+	s := NewSteps()
+	s.Add("^a calculator$", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			_, ok := s.Context.Get("result")
+			*log = append(*log, fmt.Sprintf("new calculator, has result: %t", ok))
+
+			s.Context.Set("result", 0)
+		}
+	})
+	s.Add(`^I add (\d+) and (\d+)$`, func(t Testing) func(*Step, int, int) {
+		return func(s *Step, a, b int) {
+			s.Context.Set("result", a+b)
+		}
+	})
+	s.Add(`^I subtract (\d+) from (\d+)$`, func(t Testing) func(*Step, int, int) {
+		return func(s *Step, a, b int) {
+			s.Context.Set("result", b-a)
+		}
+	})
+	s.Add(`^the result is (\d+)$`, func(t Testing) func(*Step, int) {
+		return func(s *Step, n int) {
+			v, _ := s.Context.Get("result")
+			if v.(int) != n {
+				t.Errorf("expected %d, got %d", n, v)
+			}
+
+			*log = append(*log, fmt.Sprintf("result is %d", n))
+		}
+	})
+
+	return s
+}
+
+func TestRunFeaturesRunsEachScenarioAsASubtest(t *testing.T) {
+	var log []string
+
+	RunFeatures(t, "testdata/calculator.feature", calculatorSteps(&log))
+
+	assert.Equal(t, []string{
+		"new calculator, has result: false",
+		"result is 3",
+		"new calculator, has result: false",
+		"result is 3",
+	}, log)
+}
+
+func TestRunnerNamesSubtestsByFeatureAndScenario(t *testing.T) {
+	tT := &tTesting{}
+
+	var log, names []string
+
+	r := NewRunner(calculatorSteps(&log))
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/calculator.feature")
+
+	assert.Equal(t, []string{"Calculator", "Addition", "Subtraction"}, names)
+	assert.Equal(t, []string{
+		"testdata/calculator.feature:5: Given a calculator",
+		"testdata/calculator.feature:8: When I add 1 and 2",
+		"testdata/calculator.feature:9: Then the result is 3",
+		"testdata/calculator.feature:5: Given a calculator",
+		"testdata/calculator.feature:12: When I subtract 2 from 5",
+		"testdata/calculator.feature:13: Then the result is 3",
+	}, tT.logfs)
+	assert.Equal(t, 0, len(tT.errorfs))
+}
+
+func TestRunnerReportsSyntaxErrors(t *testing.T) {
+	tT := &tTesting{}
+
+	var names []string
+
+	r := NewRunner()
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/invalid.feature")
+
+	assert.Equal(t, 0, len(names))
+	assert.Equal(t, "testdata/invalid.feature:2:3: unexpected step "+
+		"\"Given a step outside of a scenario\", steps must be within a "+
+		"Scenario or Background", tT.errorfs[0])
+}
+
+func TestRunnerFailsIfNoFeatureFilesMatch(t *testing.T) {
+	tT := &tTesting{}
+
+	NewRunner().runFiles(tT, "testdata/*.nope")
+
+	assert.Equal(t, "testdata/*.nope: no feature files found", tT.fatalfs[0])
+}
+
+func TestRunnerExpandsScenarioOutlineExamples(t *testing.T) {
+	tT := &tTesting{}
+
+	var log, names []string
+
+	r := NewRunner(calculatorSteps(&log))
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/outline.feature")
+
+	assert.Equal(t, []string{
+		"Outlines",
+		"Adding <a> and <b>",
+		"1,2,3",
+		"4,5,9",
+		"10,20,30",
+		"Subtracting",
+		"2,5,3",
+	}, names)
+	assert.Equal(t, []string{
+		"result is 3",
+		"result is 9",
+		"result is 30",
+		"result is 3",
+	}, log)
+	assert.Equal(t, "testdata/outline.feature:4: When I add 10 and 20",
+		tT.logfs[4])
+	assert.Equal(t, 0, len(tT.errorfs))
+}
+
+func TestRunnerGivesDataTablesAsATable(t *testing.T) {
+	tT := &tTesting{}
+
+	var names []string
+
+	s := NewSteps()
+	s.Add(`^the following users exist:$`, func(t Testing) func(*Step, Table) {
+		return func(s *Step, tbl Table) {
+			s.Context.Set("users", tbl.Maps())
+		}
+	})
+	s.Add(`^there is (\d+) user$`, func(t Testing) func(*Step, int) {
+		return func(s *Step, n int) {
+			v, _ := s.Context.Get("users")
+			for _, u := range v.([]map[string]string) {
+				t.Errorf("%s <%s>", u["name"], u["email"])
+			}
+		}
+	})
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/tables.feature")
+
+	assert.Equal(t, []string{
+		"Batman <bruce@wayne.com>",
+		"Robin <dick@wayne.com>",
+	}, tT.errorfs)
+}
+
+func TestRunnerGivesDocStringsAsADocString(t *testing.T) {
+	tT := &tTesting{}
+
+	var names []string
+
+	s := NewSteps()
+	s.Add(`^I (\w+) the body:$`, func(t Testing) func(string, DocString) {
+		return func(method string, d DocString) {
+			t.Errorf("%s %q: %s", method, d.MediaType, d)
+		}
+	})
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/docstrings.feature")
+
+	assert.Equal(t, []string{
+		`POST "json": {"name": "Batman"}`,
+		`PUT "": {"name": "Robin"}`,
+	}, tT.errorfs)
+}
+
+func runTags(tags string) ([]string, *tTesting) {
+	tT := &tTesting{}
+
+	var log, names []string
+
+	s := NewSteps()
+	s.Add(`^I run (\w+)$`, func(t Testing) func(string) {
+		return func(name string) {
+			log = append(log, name)
+		}
+	})
+
+	r := NewRunner(s)
+	r.Tags = tags
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/tags.feature")
+
+	return log, tT
+}
+
+// setTags sets the -gofe.tags flag and GOFE_TAGS environment variable,
+// returning a func restoring their previous values
+func setTags(flag, env string) func() {
+	f := *tagsFlag
+	e, ok := os.LookupEnv("GOFE_TAGS")
+
+	*tagsFlag = flag
+	os.Setenv("GOFE_TAGS", env)
+
+	return func() {
+		*tagsFlag = f
+		if ok {
+			os.Setenv("GOFE_TAGS", e)
+		} else {
+			os.Unsetenv("GOFE_TAGS")
+		}
+	}
+}
+
+func TestRunnerFiltersScenariosByTags(t *testing.T) {
+	defer setTags("", "")()
+
+	for _, v := range []struct {
+		tags string
+		ran  []string
+	}{
+		{"", []string{"fast", "slow", "smoke", "wip", "pay", "refund"}},
+		{"@smoke", []string{"fast", "smoke", "pay"}},
+		{"@checkout and not (@slow or @wip)",
+			[]string{"fast", "smoke", "pay", "refund"}},
+		{"@billing", []string{"pay", "refund"}},
+		{"@wip or @slow", []string{"slow", "wip"}},
+		{"@nope", nil},
+	} {
+		ran, _ := runTags(v.tags)
+
+		assert.Equal(t, v.ran, ran, v.tags)
+	}
+}
+
+func TestRunnerTagsDefaultToTheEnvironment(t *testing.T) {
+	restore := setTags("", "@slow")
+	defer restore()
+
+	ran, _ := runTags("")
+
+	assert.Equal(t, []string{"slow"}, ran)
+
+	setTags("@wip", "@slow")
+
+	ran, _ = runTags("")
+
+	assert.Equal(t, []string{"wip"}, ran)
+
+	ran, _ = runTags("@smoke")
+
+	assert.Equal(t, []string{"fast", "smoke", "pay"}, ran)
+}
+
+func TestRunnerFailsOnAnInvalidTagExpression(t *testing.T) {
+	ran, tT := runTags("@a and")
+
+	assert.True(t, ran == nil)
+	assert.Equal(t, "tag expression `@a and`: unexpected end of expression",
+		tT.fatalfs[0])
+}
+
+func TestRunnerRunsBackgroundsBeforeEachScenario(t *testing.T) {
+	tT := &tTesting{}
+
+	var log, names []string
+
+	s := NewSteps()
+	s.Add(`^I push (\w+)$`, func(t Testing) func(*Step, string) {
+		return func(s *Step, v string) {
+			var stack []string
+			if v, ok := s.Context.Get("stack"); ok {
+				stack = v.([]string)
+			}
+			stack = append(stack, v)
+
+			s.Context.Set("stack", stack)
+			log = append(log, strings.Join(stack, ","))
+		}
+	})
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/background.feature")
+
+	assert.Equal(t, []string{
+		"feature",
+		"feature,scenario",
+		"feature",
+		"feature,rule",
+		"feature,rule,scenario",
+		"feature",
+		"feature,rule",
+		"feature,rule,row",
+	}, log)
+}
+
+func TestRunnerCollectsUndefinedStepSnippets(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran, names []string
+
+	s := NewSteps()
+	s.Add(`^I run (\w+)$`, func(t Testing) func(string) {
+		return func(name string) {
+			ran = append(ran, name)
+		}
+	})
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/undefined.feature")
+
+	assert.True(t, ran == nil)
+	assert.Equal(t, []string{
+		"testdata/undefined.feature:4: `I have 42 cukes`: step not found",
+		"testdata/undefined.feature:6: `the following remain:`: step not found",
+		"testdata/undefined.feature:10: `I have 7 cukes`: step not found",
+	}, tT.errorfs)
+	assert.Equal(t, `undefined steps, implement them with:
+
+s.Add("^I have (\\d+) cukes$", func(t gofe.Testing) func(int) {
+	return func(arg1 int) {
+		// TODO implement
+	}
+})
+
+s.Add("^the following remain:$", func(t gofe.Testing) func(gofe.Table) {
+	return func(table gofe.Table) {
+		// TODO implement
+	}
+})`, tT.logfs[len(tT.logfs)-1])
+}
+
+func pendingSteps(ran *[]string) Steps {
+	s := NewSteps()
+	s.Add(`^I run (\w+)$`, func(t Testing) func(string) {
+		return func(name string) {
+			*ran = append(*ran, name)
+		}
+	})
+	s.Add("^I pay by card$", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			s.Pending("waiting on the payments API")
+		}
+	})
+	s.Add("^I refund$", func(t Testing) func() error {
+		return func() error {
+			return ErrPending
+		}
+	})
+
+	return s
+}
+
+func TestRunnerSkipsTheRestOfAPendingScenario(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran, names []string
+
+	r := NewRunner(pendingSteps(&ran))
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/pending.feature")
+
+	assert.Equal(t, []string{"first", "first"}, ran)
+	assert.Equal(t, []string{
+		"`I pay by card`: pending: waiting on the payments API",
+		"`I refund`: pending",
+	}, tT.skipfs)
+	assert.Equal(t, []string{
+		"testdata/pending.feature:4: Given I run first",
+		"testdata/pending.feature:5: When I pay by card",
+		"testdata/pending.feature:6: Then I run last (skipped)",
+		"testdata/pending.feature:9: Given I run first",
+		"testdata/pending.feature:10: When I refund",
+		"testdata/pending.feature:11: Then I run last (skipped)",
+	}, tT.logfs)
+	assert.Equal(t, 0, len(tT.errorfs)+len(tT.fatalfs))
+}
+
+func TestRunnerStrictFailsPendingScenarios(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran, names []string
+
+	r := NewRunner(pendingSteps(&ran))
+	r.Strict = true
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/pending.feature")
+
+	assert.Equal(t, []string{"first", "first"}, ran)
+	assert.Equal(t, []string{
+		"`I pay by card`: pending: waiting on the payments API",
+		"`I refund`: pending",
+	}, tT.fatalfs)
+	assert.Equal(t, 0, len(tT.skipfs))
+}
+
+func TestRunnerSkipsTheRestOfAFailedScenario(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran, names []string
+
+	s := NewSteps()
+	s.Add(`^I check (\d+)(?: and (\d+))?$`, func(t Testing) func(string, string) {
+		return func(a, b string) {
+			ran = append(ran, a)
+
+			t.Errorf("%s is wrong", a)
+			if b != "" {
+				t.Errorf("%s is wrong", b)
+			}
+		}
+	})
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/failing.feature")
+
+	assert.Equal(t, []string{"1"}, ran)
+	assert.Equal(t, []string{"1 is wrong", "2 is wrong"}, tT.errorfs)
+	assert.Equal(t, []string{
+		"testdata/failing.feature:4: Given I check 1 and 2",
+		"testdata/failing.feature:5: When I check 3 (skipped)",
+		"testdata/failing.feature:6: Then I check 4 (skipped)",
+	}, tT.logfs)
+}
+
+func TestRunnerRecoversPanicsAndRunsTheNextScenario(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran, names []string
+
+	_, _, line, _ := runtime.Caller(0)
+
+	s := NewSteps()
+	s.Add(`^I index (\d+) of "(\w+)"$`, func(t Testing) func(int, string) {
+		return func(i int, v string) {
+			ran = append(ran, v[i:i+1])
+		}
+	})
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/panic.feature")
+
+	assert.Equal(t, []string{"b"}, ran)
+	assert.Equal(t, 1, len(tT.fatalfs))
+	assert.True(t, strings.HasPrefix(tT.fatalfs[0],
+		"`I index 3 of \"abc\"`: panic: runtime error: slice bounds out of range"))
+	assert.True(t, strings.Contains(tT.fatalfs[0], fmt.Sprintf(
+		"\n\tstep: `^I index (\\d+) of \"(\\w+)\"$` (run_test.go:%d)"+
+			"\n\targs: \"3\", \"abc\""+
+			"\n\tat: testdata/panic.feature:4\n\n", line+3)))
+	assert.True(t, strings.Contains(tT.fatalfs[0], "goroutine"))
+}
+
+func TestRunnerFailsStepsGivenTheWrongNumberOfArguments(t *testing.T) {
+	var ran []string
+
+	s := NewSteps()
+	s.Add("^the following users:$", func(t Testing) func() {
+		return func() {
+			ran = append(ran, "users")
+		}
+	})
+
+	tT := &tTesting{}
+
+	var names []string
+
+	r := NewRunner(s)
+	r.run = fakeRun(&names)
+	r.runFiles(tT, "testdata/arity.feature")
+
+	assert.True(t, ran == nil)
+	assert.Equal(t, []string{
+		"`the following users:`: too many arguments\n" +
+			"\thave (gofe.Table)\n" +
+			"\twant ()",
+	}, tT.fatalfs)
+
+	tT = &tTesting{}
+
+	r.StrictArgs = false
+	r.runFiles(tT, "testdata/arity.feature")
+
+	assert.Equal(t, []string{"users"}, ran)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
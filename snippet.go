@@ -0,0 +1,102 @@
+package gofe
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// snippetArgs finds the quoted strings, floats and ints within a step name
+var snippetArgs = regexp.MustCompile(`"[^"]*"|-?\d+\.\d+|-?\d+`)
+
+// snippet returns a ready to paste Steps.Add implementing the step name, along
+// with the snippet's regex. Any quoted strings, floats and ints in the name are
+// captured as string, float64 and int arguments, followed by any explicit args
+// by their type.
+//
+//		s.Add("^I have (\\d+) cukes$", func(t gofe.Testing) func(int) {
+//			return func(arg1 int) {
+//				// TODO implement
+//			}
+//		})
+//
+func snippet(name string, a ...interface{}) (string, string) {
+	name = strings.TrimSpace(name)
+
+	var reg bytes.Buffer
+	var types []string
+
+	reg.WriteString("^")
+
+	i := 0
+	for _, m := range snippetArgs.FindAllStringIndex(name, -1) {
+		reg.WriteString(regexp.QuoteMeta(name[i:m[0]]))
+
+		switch v := name[m[0]:m[1]]; {
+		case strings.HasPrefix(v, `"`):
+			reg.WriteString(`"([^"]*)"`)
+			types = append(types, "string")
+
+		case strings.Contains(v, "."):
+			reg.WriteString(number(v, `\d+\.\d+`))
+			types = append(types, "float64")
+
+		default:
+			reg.WriteString(number(v, `\d+`))
+			types = append(types, "int")
+		}
+
+		i = m[1]
+	}
+
+	reg.WriteString(regexp.QuoteMeta(name[i:]))
+	reg.WriteString("$")
+
+	var args []string
+	for i, v := range types {
+		args = append(args, fmt.Sprintf("arg%d %s", i+1, v))
+	}
+
+	for _, v := range a {
+		var n, typ string
+		switch v.(type) {
+		case Table:
+			n, typ = "table", "gofe.Table"
+
+		case DocString:
+			n, typ = "doc", "gofe.DocString"
+
+		case nil:
+			n, typ = fmt.Sprintf("arg%d", len(args)+1), "interface{}"
+
+		default:
+			n, typ = fmt.Sprintf("arg%d", len(args)+1), reflect.TypeOf(v).String()
+		}
+
+		types = append(types, typ)
+		args = append(args, n+" "+typ)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "s.Add(%s, func(t gofe.Testing) func(%s) {\n",
+		strconv.Quote(reg.String()), strings.Join(types, ", "))
+	fmt.Fprintf(&b, "\treturn func(%s) {\n", strings.Join(args, ", "))
+	fmt.Fprintf(&b, "\t\t// TODO implement\n")
+	fmt.Fprintf(&b, "\t}\n")
+	fmt.Fprintf(&b, "})")
+
+	return reg.String(), b.String()
+}
+
+// number returns a capture group of reg, allowing a negative sign if v is
+// negative
+func number(v, reg string) string {
+	if strings.HasPrefix(v, "-") {
+		return "(-?" + reg + ")"
+	}
+
+	return "(" + reg + ")"
+}
@@ -0,0 +1,33 @@
+package gofe
+
+import (
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+func TestSnippetInfersArgTypes(t *testing.T) {
+	reg, sn := snippet(`I eat 1.5 cukes named "gherkin" -2 times`)
+
+	assert.Equal(t, `^I eat (\d+\.\d+) cukes named "([^"]*)" (-?\d+) times$`, reg)
+	assert.Equal(t, `s.Add("^I eat (\\d+\\.\\d+) cukes named \"([^\"]*)\" (-?\\d+) times$", `+
+		`func(t gofe.Testing) func(float64, string, int) {
+	return func(arg1 float64, arg2 string, arg3 int) {
+		// TODO implement
+	}
+})`, sn)
+}
+
+func TestSnippetEscapesRegexAndAddsExplicitArgs(t *testing.T) {
+	type User struct{}
+
+	reg, sn := snippet(" a + b (maybe)? ", &User{}, Table{}, DocString{})
+
+	assert.Equal(t, `^a \+ b \(maybe\)\?$`, reg)
+	assert.Equal(t, `s.Add("^a \\+ b \\(maybe\\)\\?$", `+
+		`func(t gofe.Testing) func(*gofe.User, gofe.Table, gofe.DocString) {
+	return func(arg1 *gofe.User, table gofe.Table, doc gofe.DocString) {
+		// TODO implement
+	}
+})`, sn)
+}
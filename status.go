@@ -0,0 +1,172 @@
+package gofe
+
+import (
+	"errors"
+)
+
+// ErrPending can be returned by a step func to mark the step as pending, the
+// same as calling Step.Pending
+//
+//		s.Add("^I pay by card$", func(t Testing) func() error {
+//			return func() error {
+//				return gofe.ErrPending
+//			}
+//		})
+//
+var ErrPending = errors.New("pending")
+
+// Status is the outcome of the steps run by a Feature
+type Status int
+
+const (
+	Passed Status = iota
+	Failed
+	Pending
+)
+
+func (s Status) String() string {
+	switch s {
+	case Passed:
+		return "passed"
+	case Failed:
+		return "failed"
+	case Pending:
+		return "pending"
+	}
+
+	return "unknown"
+}
+
+// Status returns Pending once any step is pending, Failed if any step or the
+// Testing has failed and otherwise Passed
+func (f Feature) Status() Status {
+	if f.isPending() {
+		return Pending
+	}
+	if f.isFailed() || f.T.Failed() {
+		return Failed
+	}
+
+	return Passed
+}
+
+// Pending marks the step as not yet implemented. The step is skipped through
+// Testing's Skipf, or failed if the Feature is Strict, and any remaining steps
+// are skipped. As with Skipf, Pending stops the step func.
+//
+//		s.Add("^I pay by card$", func(t Testing) func(*Step) {
+//			return func(s *Step) {
+//				s.Pending("waiting on the payments API")
+//			}
+//		})
+//
+func (s *Step) Pending(msg string) {
+	s.Feature.pend(s.name, msg)
+}
+
+func (f Feature) isPending() bool {
+	return f.pending != nil && *f.pending
+}
+
+func (f Feature) isFailed() bool {
+	return f.failed != nil && *f.failed
+}
+
+// halted checks if the remaining steps are to be skipped
+func (f Feature) halted() bool {
+	return f.isPending() || f.SkipOnFailure && f.isFailed()
+}
+
+// track marks the Feature failed if the step failed
+func (f Feature) track(w *watch) {
+	if w.stepFailed() && f.failed != nil {
+		*f.failed = true
+	}
+}
+
+// watch wraps the Testing of a step, recording any failure reported through it
+// so a step fails even if the Testing had already failed
+type watch struct {
+	Testing
+
+	before bool
+	failed bool
+}
+
+func watching(t Testing) *watch {
+	return &watch{
+		Testing: t,
+
+		before: t.Failed(),
+	}
+}
+
+// stepFailed checks if the step failed through the watch, or otherwise the
+// Testing went from passing to failed, as for a StepFunc given a *testing.T
+func (w *watch) stepFailed() bool {
+	return w.failed || !w.before && w.Testing.Failed()
+}
+
+func (w *watch) Fail() {
+	w.failed = true
+	w.Testing.Fail()
+}
+
+func (w *watch) FailNow() {
+	w.failed = true
+	w.Testing.FailNow()
+}
+
+func (w *watch) Error(args ...interface{}) {
+	w.Testing.Helper()
+	w.failed = true
+	w.Testing.Error(args...)
+}
+
+func (w *watch) Errorf(format string, args ...interface{}) {
+	w.Testing.Helper()
+	w.failed = true
+	w.Testing.Errorf(format, args...)
+}
+
+func (w *watch) Fatal(args ...interface{}) {
+	w.Testing.Helper()
+	w.failed = true
+	w.Testing.Fatal(args...)
+}
+
+func (w *watch) Fatalf(format string, args ...interface{}) {
+	w.Testing.Helper()
+	w.failed = true
+	w.Testing.Fatalf(format, args...)
+}
+
+// pend marks the Feature pending, skipping or failing the step
+func (f Feature) pend(name, msg string) {
+	if f.pending != nil {
+		*f.pending = true
+	}
+
+	if msg != "" {
+		msg = ": " + msg
+	}
+
+	if f.Strict {
+		f.T.Fatalf("`%s`: pending%s", name, msg)
+
+		return // testing package will exit, this is for tests
+	}
+
+	f.T.Skipf("`%s`: pending%s", name, msg)
+}
+
+// skipped checks if the Feature is halted, logging the step as skipped
+func (f Feature) skipped(name string) bool {
+	if !f.halted() {
+		return false
+	}
+
+	f.T.Logf("`%s`: skipped", name)
+
+	return true
+}
@@ -0,0 +1,254 @@
+package gofe
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+func TestPendingSkipsTheRemainingSteps(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []string
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func() {
+		return func() {
+			ran = append(ran, "a step")
+		}
+	})
+	s.Add("a pending step", func(t Testing) func(*Step) {
+		return func(s *Step) {
+			s.Pending("not yet")
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a step")
+	assert.Equal(t, Passed, fe.Status())
+
+	fe.Step("a pending step")
+	fe.Step("a step")
+	fe.Step("an undefined step")
+
+	assert.Equal(t, []string{"a step"}, ran)
+	assert.Equal(t, Pending, fe.Status())
+	assert.Equal(t, []string{"`a pending step`: pending: not yet"}, tT.skipfs)
+	assert.Equal(t, []string{
+		"`a step`: skipped",
+		"`an undefined step`: skipped",
+	}, tT.logfs)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestStrictFailsPendingSteps(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add("a pending step", func(t Testing) func() error {
+		return func() error {
+			return ErrPending
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Strict = true
+	fe.Step("a pending step")
+
+	assert.Equal(t, Pending, fe.Status())
+	assert.Equal(t, []string{"`a pending step`: pending"}, tT.fatalfs)
+	assert.Equal(t, 0, len(tT.skipfs))
+}
+
+func TestStepFuncErrorsFailTheStep(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func(bool) error {
+		return func(fail bool) error {
+			if fail {
+				return fmt.Errorf("boom")
+			}
+
+			return nil
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a step", false)
+	assert.Equal(t, Passed, fe.Status())
+
+	fe.Step("a step", true)
+	assert.Equal(t, Failed, fe.Status())
+	assert.Equal(t, []string{"`a step`: boom"}, tT.fatalfs)
+}
+
+func TestStepFuncResultsOtherThanAnErrorAreIgnored(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add("a bool", func(t Testing) func() bool {
+		return func() bool {
+			return false
+		}
+	})
+	s.Add("an error", func(t Testing) func() error {
+		return func() error {
+			return fmt.Errorf("boom")
+		}
+	})
+	s.Add("an error last", func(t Testing) func() (bool, error) {
+		return func() (bool, error) {
+			return true, ErrPending
+		}
+	})
+	s.Add("an error first", func(t Testing) func() (error, bool) {
+		return func() (error, bool) {
+			return fmt.Errorf("ignored"), true
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a bool")
+	fe.Step("an error first")
+	fe.Step("an error")
+	fe.Step("an error last")
+
+	assert.Equal(t, []string{"`an error`: boom"}, tT.fatalfs)
+	assert.Equal(t, []string{"`an error last`: pending"}, tT.skipfs)
+}
+
+func TestStatusString(t *testing.T) {
+	assert.Equal(t, "passed", Passed.String())
+	assert.Equal(t, "failed", Failed.String())
+	assert.Equal(t, "pending", Pending.String())
+}
+
+func failingSteps(ran *[]string) Steps {
+	s := NewSteps()
+	s.Add(`^step (\d+) fails$`, func(t Testing) func(string) {
+		return func(n string) {
+			*ran = append(*ran, n)
+
+			t.Errorf("step %s failed", n)
+			t.Errorf("step %s failed again", n)
+		}
+	})
+
+	return s
+}
+
+func TestStepsAreRunAfterAFailureByDefault(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []string
+
+	fe := New(tT, failingSteps(&ran))
+	fe.Step("step 1 fails")
+	fe.Step("step 2 fails")
+
+	assert.Equal(t, []string{"1", "2"}, ran)
+	assert.Equal(t, Failed, fe.Status())
+	assert.Equal(t, 4, len(tT.errorfs))
+}
+
+func TestSkipOnFailureSkipsTheRemainingSteps(t *testing.T) {
+	tT := &tTesting{}
+
+	var ran []string
+
+	fe := New(tT, failingSteps(&ran))
+	fe.SkipOnFailure = true
+	fe.Step("step 1 fails")
+	fe.Step("step 2 fails")
+	fe.Step("an undefined step")
+
+	assert.Equal(t, []string{"1"}, ran)
+	assert.Equal(t, Failed, fe.Status())
+	assert.Equal(t, []string{
+		"step 1 failed",
+		"step 1 failed again",
+	}, tT.errorfs)
+	assert.Equal(t, []string{
+		"`step 2 fails`: skipped",
+		"`an undefined step`: skipped",
+	}, tT.logfs)
+	assert.Equal(t, 0, len(tT.fatalfs))
+}
+
+func TestSkipOnFailureIgnoresFailuresBeforeTheFeature(t *testing.T) {
+	tT := &tTesting{}
+	tT.Errorf("an earlier failure")
+
+	var ran []string
+
+	s := NewSteps()
+	s.Add("a step", func(t Testing) func() {
+		return func() {
+			ran = append(ran, "a step")
+		}
+	})
+
+	fe := New(tT, s)
+	fe.SkipOnFailure = true
+	fe.Step("a step")
+	fe.Step("a step")
+
+	assert.Equal(t, []string{"a step", "a step"}, ran)
+}
+
+func TestSkipOnFailureSkipsStepsFailingAfterAFailureBeforeTheFeature(t *testing.T) {
+	tT := &tTesting{}
+	tT.Errorf("an earlier failure")
+
+	var ran []string
+
+	s := NewSteps()
+	s.Add("^fail (\\w+)$", func(t Testing) func(string) {
+		return func(name string) {
+			ran = append(ran, name)
+			t.Errorf("%s failed", name)
+		}
+	})
+
+	fe := New(tT, s)
+	fe.SkipOnFailure = true
+	fe.Step("fail a")
+	fe.Step("fail b")
+
+	assert.Equal(t, []string{"a"}, ran)
+	assert.Equal(t, []string{"`fail b`: skipped"}, tT.logfs)
+}
+
+func TestStepPanicsAreRecovered(t *testing.T) {
+	tT := &tTesting{}
+
+	_, _, line, _ := runtime.Caller(0)
+
+	s := NewSteps()
+	s.Add("a panicking step", func(t Testing) func(Table) {
+		return func(Table) {
+			panic("boom")
+		}
+	})
+
+	fe := New(tT, s)
+	fe.Step("a panicking step", Table{{"a"}})
+	fe.Stepf(func(t Testing) func() {
+		return func() {
+			panic("boom")
+		}
+	})
+
+	assert.Equal(t, Failed, fe.Status())
+	assert.Equal(t, 2, len(tT.fatalfs))
+	assert.True(t, strings.HasPrefix(tT.fatalfs[0], fmt.Sprintf(
+		"`a panicking step`: panic: boom"+
+			"\n\tstep: `a panicking step` (status_test.go:%d)"+
+			"\n\targs: gofe.Table{[]string{\"a\"}}"+
+			"\n\ngoroutine ", line+3)))
+	assert.True(t, strings.HasPrefix(tT.fatalfs[1], "``: panic: boom\n\ngoroutine "))
+}
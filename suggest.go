@@ -0,0 +1,165 @@
+package gofe
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the most steps suggested for an unmatched step name
+const maxSuggestions = 3
+
+// literal returns the literal text of a regex, skipping any capture groups,
+// repetitions and all but the first of any alternation. Cucumber Expressions
+// are compiled to regex, so this works for both.
+func literal(reg string) string {
+	re, err := syntax.Parse(reg, syntax.Perl)
+	if err != nil {
+		return reg
+	}
+
+	var b bytes.Buffer
+	writeLiteral(&b, re)
+
+	return normalize(b.String())
+}
+
+func writeLiteral(b *bytes.Buffer, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+
+	case syntax.OpConcat:
+		for _, v := range re.Sub {
+			writeLiteral(b, v)
+		}
+
+	case syntax.OpAlternate:
+		writeLiteral(b, re.Sub[0])
+	}
+}
+
+// normalize lowercases and collapses the whitespace of s
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// distance returns the Levenshtein distance between a and b
+func distance(a, b string) int {
+	r, s := []rune(a), []rune(b)
+
+	prev := make([]int, len(s)+1)
+	curr := make([]int, len(s)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(r); i++ {
+		curr[0] = i
+		for j := 1; j <= len(s); j++ {
+			cost := 1
+			if r[i-1] == s[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(s)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
+
+type suggestion struct {
+	*step
+
+	d int
+}
+
+type byDistance []suggestion
+
+func (s byDistance) Len() int      { return len(s) }
+func (s byDistance) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDistance) Less(i, j int) bool {
+	if s[i].d != s[j].d {
+		return s[i].d < s[j].d
+	}
+
+	return s[i].name < s[j].name
+}
+
+// suggest returns the steps whose literal text is closest to the name, with
+// any ints, floats and quoted strings removed from the name. Only steps within
+// half the length of the longer text are suggested.
+func (f Feature) suggest(name string) []*step {
+	text := normalize(snippetArgs.ReplaceAllString(name, ""))
+
+	var ss []suggestion
+
+	seen := make(map[*step]bool)
+	for _, s := range f.Steps {
+		for _, v := range s.steps {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+
+			lit := literal(v.full.String())
+
+			n := len(text)
+			if len(lit) > n {
+				n = len(lit)
+			}
+
+			d := distance(text, lit)
+			if d*2 <= n {
+				ss = append(ss, suggestion{v, d})
+			}
+		}
+	}
+
+	sort.Sort(byDistance(ss))
+
+	var steps []*step
+	for i := 0; i < len(ss) && i < maxSuggestions; i++ {
+		steps = append(steps, ss[i].step)
+	}
+
+	return steps
+}
+
+// notFound returns the step not found message, with any suggested steps
+func (f Feature) notFound(name string) string {
+	steps := f.suggest(name)
+	if len(steps) == 0 {
+		return fmt.Sprintf("`%s`: step not found", name)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "`%s`: step not found, did you mean:", name)
+	writeSteps(&b, steps...)
+
+	return b.String()
+}
+
+// writeSteps writes each step's name and where it was added on a new line
+func writeSteps(b *bytes.Buffer, steps ...*step) {
+	for _, v := range steps {
+		fmt.Fprintf(b, "\n\t`%s` (%s:%d)", v.name, filepath.Base(v.file), v.line)
+	}
+}
@@ -0,0 +1,69 @@
+package gofe
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+func TestLiteralSkipsCapturesAndRepetitions(t *testing.T) {
+	for _, v := range []struct {
+		reg, lit string
+	}{
+		{`^I have (\d+) cukes$`, "i have cukes"},
+		{`^I eat "([^"]*)" \+ more?$`, `i eat "" + mor`},
+		{`^(?:is|are) ready$`, "is ready"},
+		{`[`, "["},
+	} {
+		assert.Equal(t, v.lit, literal(v.reg))
+	}
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("cukes", "cukes"))
+	assert.Equal(t, 3, distance("kitten", "sitting"))
+	assert.Equal(t, 5, distance("", "cukes"))
+	assert.Equal(t, 5, distance("cukes", ""))
+}
+
+func TestStepNotFoundSuggestsTheClosestSteps(t *testing.T) {
+	tT := &tTesting{}
+
+	fn := func(t Testing) func(int) {
+		return func(int) {}
+	}
+
+	_, _, line, _ := runtime.Caller(0)
+
+	s := NewSteps()
+	s.Add(`^I have (\d+) cukes$`, fn)
+	s.AddExpr("I have {int} cucumber(s)", fn)
+	s.Add(`^I eat (\d+) cukes$`, fn)
+	s.Add(`^I have (\d+) cukes in my belly$`, fn)
+	s.Add(`^the belly is full$`, fn)
+
+	fe := New(tT, s)
+	fe.Step("I hav 5 cukes")
+
+	assert.Equal(t, fmt.Sprintf("`I hav 5 cukes`: step not found, did you mean:"+
+		"\n\t`^I have (\\d+) cukes$` (suggest_test.go:%d)"+
+		"\n\t`^I eat (\\d+) cukes$` (suggest_test.go:%d)"+
+		"\n\t`I have {int} cucumber(s)` (suggest_test.go:%d)",
+		line+3, line+5, line+4), tT.fatalfs[0])
+}
+
+func TestStepNotFoundWithoutAnyCloseSteps(t *testing.T) {
+	tT := &tTesting{}
+
+	s := NewSteps()
+	s.Add("^the belly is full$", func(t Testing) func() {
+		return func() {}
+	})
+
+	fe := New(tT, s)
+	fe.Step("I have 5 cukes")
+
+	assert.Equal(t, "`I have 5 cukes`: step not found", tT.fatalfs[0])
+}
@@ -0,0 +1,124 @@
+package gofe
+
+// Table is a data table step argument. A step func can declare a Table as its
+// last argument, after any *Step and regex arguments, and will be given either
+// the step's Gherkin data table or a Table passed explicitly as the last
+// argument of the step.
+//
+//		s.Add("^the following users exist:$", func(t Testing) func(Table) {
+//			return func(tbl Table) {
+//				for _, u := range tbl.Maps() {
+//					t.Logf("%s <%s>", u["name"], u["email"])
+//				}
+//			}
+//		})
+//
+//		fe.Step("the following users exist:", gofe.Table{
+//			{"name", "email"},
+//			{"Batman", "bruce@wayne.com"},
+//		})
+//
+type Table [][]string
+
+// tableOf returns the Table of a Gherkin data table
+func tableOf(rows []*TableRow) Table {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	t := make(Table, len(rows))
+	for i, v := range rows {
+		t[i] = v.Values()
+	}
+
+	return t
+}
+
+// Raw returns all rows, including the header
+func (t Table) Raw() [][]string {
+	return [][]string(t)
+}
+
+// Header returns the first row
+func (t Table) Header() []string {
+	if len(t) == 0 {
+		return nil
+	}
+
+	return t[0]
+}
+
+// Rows returns all rows after the header
+func (t Table) Rows() [][]string {
+	if len(t) < 2 {
+		return nil
+	}
+
+	return t[1:]
+}
+
+// Maps returns each row after the header as a map keyed by the header
+func (t Table) Maps() []map[string]string {
+	h := t.Header()
+
+	var m []map[string]string
+	for _, row := range t.Rows() {
+		r := make(map[string]string, len(h))
+		for i, k := range h {
+			if i < len(row) {
+				r[k] = row[i]
+			}
+		}
+
+		m = append(m, r)
+	}
+
+	return m
+}
+
+// Transpose returns a new Table with the rows and columns swapped
+func (t Table) Transpose() Table {
+	var n int
+	for _, row := range t {
+		if len(row) > n {
+			n = len(row)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	tr := make(Table, n)
+	for i := range tr {
+		tr[i] = make([]string, len(t))
+		for j, row := range t {
+			if i < len(row) {
+				tr[i][j] = row[i]
+			}
+		}
+	}
+
+	return tr
+}
+
+// Cell returns the value at row i and column j, the header being row 0. An
+// empty string is returned if the cell does not exist.
+func (t Table) Cell(i, j int) string {
+	if i < 0 || i >= len(t) || j < 0 || j >= len(t[i]) {
+		return ""
+	}
+
+	return t[i][j]
+}
+
+// Get returns the value of the column named by the header for row i, row 0
+// being the first row after the header
+func (t Table) Get(i int, name string) string {
+	for j, k := range t.Header() {
+		if k == name {
+			return t.Cell(i+1, j)
+		}
+	}
+
+	return ""
+}
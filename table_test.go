@@ -0,0 +1,50 @@
+package gofe
+
+import (
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+var users = Table{
+	{"name", "email"},
+	{"Batman", "bruce@wayne.com"},
+	{"Robin", "dick@wayne.com"},
+}
+
+func TestTableRowsAndHeader(t *testing.T) {
+	assert.Equal(t, [][]string(users), users.Raw())
+	assert.Equal(t, []string{"name", "email"}, users.Header())
+	assert.Equal(t, [][]string{
+		{"Batman", "bruce@wayne.com"},
+		{"Robin", "dick@wayne.com"},
+	}, users.Rows())
+
+	var empty Table
+	assert.True(t, empty.Header() == nil)
+	assert.True(t, empty.Rows() == nil)
+}
+
+func TestTableMaps(t *testing.T) {
+	assert.Equal(t, []map[string]string{
+		{"name": "Batman", "email": "bruce@wayne.com"},
+		{"name": "Robin", "email": "dick@wayne.com"},
+	}, users.Maps())
+}
+
+func TestTableTranspose(t *testing.T) {
+	assert.Equal(t, Table{
+		{"name", "Batman", "Robin"},
+		{"email", "bruce@wayne.com", "dick@wayne.com"},
+	}, users.Transpose())
+}
+
+func TestTableCellAccess(t *testing.T) {
+	assert.Equal(t, "email", users.Cell(0, 1))
+	assert.Equal(t, "Robin", users.Cell(2, 0))
+	assert.Equal(t, "", users.Cell(3, 0))
+	assert.Equal(t, "", users.Cell(0, 2))
+
+	assert.Equal(t, "dick@wayne.com", users.Get(1, "email"))
+	assert.Equal(t, "", users.Get(1, "phone"))
+}
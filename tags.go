@@ -0,0 +1,192 @@
+package gofe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagExpr is a parsed Cucumber tag expression, eg.
+//
+//		@smoke and not (@slow or @wip)
+//
+type TagExpr interface {
+	// Match returns true if the given tags satisfy the expression
+	Match(tags []string) bool
+}
+
+type tagAll struct{}
+
+func (tagAll) Match([]string) bool {
+	return true
+}
+
+type tagName string
+
+func (n tagName) Match(tags []string) bool {
+	for _, v := range tags {
+		if v == string(n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type tagNot struct {
+	x TagExpr
+}
+
+func (e tagNot) Match(tags []string) bool {
+	return !e.x.Match(tags)
+}
+
+type tagAnd struct {
+	a, b TagExpr
+}
+
+func (e tagAnd) Match(tags []string) bool {
+	return e.a.Match(tags) && e.b.Match(tags)
+}
+
+type tagOr struct {
+	a, b TagExpr
+}
+
+func (e tagOr) Match(tags []string) bool {
+	return e.a.Match(tags) || e.b.Match(tags)
+}
+
+// ParseTagExpr parses a tag expression of @tags combined with and, or, not and
+// parentheses. An empty expression matches everything.
+func ParseTagExpr(s string) (TagExpr, error) {
+	p := &tagParser{
+		src:  s,
+		toks: tokenizeTags(s),
+	}
+	if len(p.toks) == 0 {
+		return tagAll{}, nil
+	}
+
+	x, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, p.errorf("unexpected %q", tok)
+	}
+
+	return x, nil
+}
+
+// tokenizeTags splits a tag expression into its tags, operators and parens
+func tokenizeTags(s string) []string {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+
+	return strings.Fields(s)
+}
+
+// tagParser is a recursive descent parser, where not binds tighter than and,
+// which binds tighter than or
+type tagParser struct {
+	src  string
+	toks []string
+	i    int
+}
+
+func (p *tagParser) errorf(f string, v ...interface{}) error {
+	return fmt.Errorf("tag expression `%s`: %s", p.src, fmt.Sprintf(f, v...))
+}
+
+func (p *tagParser) peek() string {
+	if p.i < len(p.toks) {
+		return p.toks[p.i]
+	}
+
+	return ""
+}
+
+func (p *tagParser) next() string {
+	tok := p.peek()
+	p.i++
+
+	return tok
+}
+
+func (p *tagParser) or() (TagExpr, error) {
+	a, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "or" {
+		p.next()
+
+		b, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		a = tagOr{a, b}
+	}
+
+	return a, nil
+}
+
+func (p *tagParser) and() (TagExpr, error) {
+	a, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "and" {
+		p.next()
+
+		b, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		a = tagAnd{a, b}
+	}
+
+	return a, nil
+}
+
+func (p *tagParser) not() (TagExpr, error) {
+	if p.peek() == "not" {
+		p.next()
+
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		return tagNot{x}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *tagParser) primary() (TagExpr, error) {
+	switch tok := p.next(); {
+	case tok == "":
+		return nil, p.errorf("unexpected end of expression")
+
+	case tok == "(":
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, p.errorf("missing )")
+		}
+
+		return x, nil
+
+	case strings.HasPrefix(tok, "@") && len(tok) > 1:
+		return tagName(tok), nil
+
+	default:
+		return nil, p.errorf("unexpected %q", tok)
+	}
+}
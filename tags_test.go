@@ -0,0 +1,54 @@
+package gofe
+
+import (
+	"testing"
+
+	"gopkg.in/nowk/assert.v2"
+)
+
+func TestTagExprMatch(t *testing.T) {
+	for _, v := range []struct {
+		expr string
+		tags []string
+		ok   bool
+	}{
+		{"", nil, true},
+		{"@smoke", []string{"@smoke"}, true},
+		{"@smoke", []string{"@slow"}, false},
+		{"not @smoke", []string{"@slow"}, true},
+		{"@a and @b", []string{"@a"}, false},
+		{"@a and @b", []string{"@b", "@a"}, true},
+		{"@a or @b", []string{"@b"}, true},
+		{"@a or @b and @c", []string{"@a"}, true},
+		{"(@a or @b) and @c", []string{"@a"}, false},
+		{"@smoke and not (@slow or @wip)", []string{"@smoke"}, true},
+		{"@smoke and not (@slow or @wip)", []string{"@smoke", "@wip"}, false},
+		{"not not @a", []string{"@a"}, true},
+	} {
+		x, err := ParseTagExpr(v.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, v.ok, x.Match(v.tags), v.expr)
+	}
+}
+
+func TestTagExprSyntaxErrors(t *testing.T) {
+	for _, v := range []struct {
+		expr, err string
+	}{
+		{"@a and", "tag expression `@a and`: unexpected end of expression"},
+		{"(@a or @b", "tag expression `(@a or @b`: missing )"},
+		{"@a @b", "tag expression `@a @b`: unexpected \"@b\""},
+		{"smoke", "tag expression `smoke`: unexpected \"smoke\""},
+		{"@a)", "tag expression `@a)`: unexpected \")\""},
+	} {
+		_, err := ParseTagExpr(v.expr)
+		if err == nil {
+			t.Fatalf("expected error for %q", v.expr)
+		}
+
+		assert.Equal(t, v.err, err.Error())
+	}
+}
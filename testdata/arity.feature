@@ -0,0 +1,6 @@
+Feature: Arity
+
+  Scenario: An unexpected table
+    Given the following users:
+      | name   |
+      | Batman |
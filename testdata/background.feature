@@ -0,0 +1,22 @@
+Feature: Backgrounds
+
+  Background:
+    Given I push feature
+
+  Scenario: Top level
+    Given I push scenario
+
+  Rule: A rule
+
+    Background:
+      Given I push rule
+
+    Scenario: In rule
+      Given I push scenario
+
+    Scenario Outline: Outline in rule
+      Given I push <v>
+
+      Examples:
+        | v   |
+        | row |
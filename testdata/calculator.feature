@@ -0,0 +1,13 @@
+Feature: Calculator
+  Simple arithmetic
+
+  Background:
+    Given a calculator
+
+  Scenario: Addition
+    When I add 1 and 2
+    Then the result is 3
+
+  Scenario: Subtraction
+    When I subtract 2 from 5
+    Then the result is 3
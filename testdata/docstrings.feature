@@ -0,0 +1,17 @@
+Feature: Doc strings
+
+  Scenario: Body
+    When I POST the body:
+      """json
+      {"name": "Batman"}
+      """
+
+  Scenario Outline: Outline body
+    When I PUT the body:
+      ```
+      {"name": "<name>"}
+      ```
+
+    Examples:
+      | name  |
+      | Robin |
@@ -0,0 +1,6 @@
+Feature: Failing
+
+  Scenario: Broken state
+    Given I check 1 and 2
+    When I check 3
+    Then I check 4
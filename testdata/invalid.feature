@@ -0,0 +1,2 @@
+Feature: Broken
+  Given a step outside of a scenario
@@ -0,0 +1,22 @@
+Feature: Outlines
+
+  Scenario Outline: Adding <a> and <b>
+    When I add <a> and <b>
+    Then the result is <c>
+
+    Examples:
+      | a | b | c  |
+      | 1 | 2 | 3  |
+      | 4 | 5 | 9  |
+
+    Scenarios: larger numbers
+      | a  | b  | c   |
+      | 10 | 20 | 30  |
+
+  Scenario Template: Subtracting
+    When I subtract <a> from <b>
+    Then the result is <c>
+
+    Examples:
+      | a | b | c |
+      | 2 | 5 | 3 |
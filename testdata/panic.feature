@@ -0,0 +1,8 @@
+Feature: Panics
+
+  Scenario: Panicking
+    Given I index 3 of "abc"
+    Then I index 0 of "abc"
+
+  Scenario: Next
+    Given I index 1 of "abc"
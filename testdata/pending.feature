@@ -0,0 +1,11 @@
+Feature: Pending
+
+  Scenario: Paying
+    Given I run first
+    When I pay by card
+    Then I run last
+
+  Scenario: Refunding
+    Given I run first
+    When I refund
+    Then I run last
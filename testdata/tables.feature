@@ -0,0 +1,17 @@
+Feature: Tables
+
+  Scenario: Users
+    Given the following users exist:
+      | name   | email           |
+      | Batman | bruce@wayne.com |
+    Then there is 1 user
+
+  Scenario Outline: Outline users
+    Given the following users exist:
+      | name   | email   |
+      | <name> | <email> |
+    Then there is 1 user
+
+    Examples:
+      | name  | email          |
+      | Robin | dick@wayne.com |
@@ -0,0 +1,33 @@
+@checkout
+Feature: Tags
+
+  @smoke
+  Scenario: Fast
+    Given I run fast
+
+  @slow
+  Scenario: Slow
+    Given I run slow
+
+  Scenario Outline: Outline
+    Given I run <name>
+
+    @smoke
+    Examples:
+      | name  |
+      | smoke |
+
+    @wip
+    Examples:
+      | name |
+      | wip  |
+
+  @billing
+  Rule: Billing
+
+    @smoke
+    Scenario: Pay
+      Given I run pay
+
+    Scenario: Refund
+      Given I run refund
@@ -0,0 +1,11 @@
+Feature: Undefined
+
+  Scenario: New feature
+    Given I have 42 cukes
+    And I run defined
+    Then the following remain:
+      | name |
+
+  Scenario: Again
+    Given I have 7 cukes
+    And I run defined